@@ -0,0 +1,72 @@
+package filter
+
+import (
+	"net"
+
+	"github.com/multiformats/go-multiaddr"
+)
+
+// AllAddrsBlocked returns true if every address in addrs is denied by the
+// Filters set, which is when a dialer should consider the peer undialable.
+// It returns false for an empty list.
+//
+// To check a peer.AddrInfo, pass its Addrs field; this package does not
+// depend on go-libp2p-core.
+func AllAddrsBlocked(fs *Filters, addrs []multiaddr.Multiaddr) bool {
+	if len(addrs) == 0 {
+		return false
+	}
+	for _, a := range addrs {
+		if !fs.AddrBlocked(a) {
+			return false
+		}
+	}
+	return true
+}
+
+// IPBlocked applies the Filters set to a bare IP, returning true if it is
+// denied. Invalid IPs are subject to the default policy, like unparseable
+// multiaddrs are in AddrBlocked.
+func IPBlocked(fs *Filters, ip net.IP) bool {
+	addr, err := ipMultiaddr(ip)
+	if err != nil {
+		return fs.DefaultAction == ActionDeny
+	}
+	return fs.AddrBlocked(addr)
+}
+
+// AnyBlocked returns true if any of the IPs is denied by the Filters set,
+// stopping at the first denied IP. Each IP is checked separately, so rules
+// changed concurrently may apply to some of the IPs and not others.
+func AnyBlocked(fs *Filters, ips []net.IP) bool {
+	for _, ip := range ips {
+		if IPBlocked(fs, ip) {
+			return true
+		}
+	}
+	return false
+}
+
+// BlockedMask returns, for each address in addrs, whether the Filters set
+// denies it. The result is index-aligned with addrs, so it can be used to
+// filter the original slice. As with AnyBlocked, the verdicts are not taken
+// from a single snapshot of the rules.
+func BlockedMask(fs *Filters, addrs []multiaddr.Multiaddr) []bool {
+	mask := make([]bool, len(addrs))
+	for i, a := range addrs {
+		mask[i] = fs.AddrBlocked(a)
+	}
+	return mask
+}
+
+// AddrAllowed returns true if the Filters set accepts the address. It is the
+// inverse of Filters.AddrBlocked.
+func AddrAllowed(fs *Filters, a multiaddr.Multiaddr) bool {
+	return !fs.AddrBlocked(a)
+}
+
+// IPAllowed returns true if the Filters set accepts the IP. It is the
+// inverse of IPBlocked.
+func IPAllowed(fs *Filters, ip net.IP) bool {
+	return !IPBlocked(fs, ip)
+}
@@ -0,0 +1,37 @@
+package filter
+
+import "net"
+
+// AddFilterChanged adds a rule like Filters.AddFilter, and returns true if
+// the rule set changed as a result: either the network had no rule yet, or
+// its rule had a different action. Removals already report this through the
+// return value of Filters.RemoveLiteral.
+//
+// Host bits set in ipnet's IP beyond its mask are cleared before the rule is
+// added, so that 1.2.3.4/24 and 1.2.3.0/24 are recognized as the same rule.
+//
+// The result reflects the rules as they were just before the update; if
+// another goroutine changes the same rule in between, it may be stale.
+func AddFilterChanged(fs *Filters, ipnet net.IPNet, action Action) bool {
+	ipnet = canonicalNet(ipnet)
+	key := ipnet.String()
+	changed := true
+	for _, r := range Rules(fs) {
+		if r.Net.String() == key {
+			changed = r.Action != action
+			break
+		}
+	}
+
+	fs.AddFilter(ipnet, action)
+	return changed
+}
+
+// canonicalNet clears the host bits of ipnet's IP. Networks whose IP and
+// mask lengths don't match are returned unchanged.
+func canonicalNet(ipnet net.IPNet) net.IPNet {
+	if ip := ipnet.IP.Mask(ipnet.Mask); ip != nil {
+		ipnet.IP = ip
+	}
+	return ipnet
+}
@@ -0,0 +1,35 @@
+package filter
+
+import (
+	"net"
+	"testing"
+)
+
+func TestAddFilterChangedNonCanonical(t *testing.T) {
+	fs := NewFilters()
+	_, canonical, _ := net.ParseCIDR("1.2.3.0/24")
+	nonCanonical := net.IPNet{IP: net.ParseIP("1.2.3.4").To4(), Mask: net.CIDRMask(24, 32)}
+
+	if !AddFilterChanged(fs, nonCanonical, ActionDeny) {
+		t.Fatal("adding a new rule should report a change")
+	}
+	if AddFilterChanged(fs, *canonical, ActionDeny) {
+		t.Fatal("re-adding the canonical form should not report a change")
+	}
+	if AddFilterChanged(fs, nonCanonical, ActionDeny) {
+		t.Fatal("re-adding the non-canonical form should not report a change")
+	}
+	if !AddFilterChanged(fs, nonCanonical, ActionAccept) {
+		t.Fatal("changing the action should report a change")
+	}
+	if n := len(Rules(fs)); n != 1 {
+		t.Fatalf("expected 1 rule, got %d: %v", n, Rules(fs))
+	}
+
+	if !fs.RemoveLiteral(*canonical) {
+		t.Fatal("RemoveLiteral should remove the rule by its canonical form")
+	}
+	if n := len(Rules(fs)); n != 0 {
+		t.Fatalf("expected no rules, got %v", Rules(fs))
+	}
+}
@@ -0,0 +1,56 @@
+package filter
+
+import (
+	"fmt"
+
+	"github.com/multiformats/go-multiaddr"
+)
+
+// CheckDial returns a descriptive error if the Filters set denies the
+// address, naming the rule responsible, and nil if the address is accepted.
+func CheckDial(fs *Filters, a multiaddr.Multiaddr) error {
+	if !fs.AddrBlocked(a) {
+		return nil
+	}
+	return fmt.Errorf("dial to %s blocked by %s", a, blockingRule(fs, a))
+}
+
+// blockingRule describes the rule that denies a blocked address. Since the
+// last matching rule wins, that is the last deny rule containing the
+// address' IP, if any, or the default policy otherwise.
+func blockingRule(fs *Filters, a multiaddr.Multiaddr) string {
+	ip, found := addrIP(a)
+	if !found {
+		return "default policy"
+	}
+
+	rule := "default policy"
+	for _, ipnet := range fs.FiltersForAction(ActionDeny) {
+		if ipnet.Contains(ip) {
+			rule = ipnet.String()
+		}
+	}
+	return rule
+}
+
+// ObservedAddrBlocked checks a peer's observed address, as reported by
+// identify, along with the addresses it advertises. It returns true if any
+// of them is denied, with a reason naming the offending address and rule.
+// The observed address is checked first, as it reflects the peer's real IP;
+// a peer whose observed address is denied while its advertised addresses are
+// accepted may be advertising spoofed addresses.
+func ObservedAddrBlocked(fs *Filters, observed multiaddr.Multiaddr, advertised []multiaddr.Multiaddr) (bool, string) {
+	if observed != nil && fs.AddrBlocked(observed) {
+		reason := fmt.Sprintf("observed address %s blocked by %s", observed, blockingRule(fs, observed))
+		if len(advertised) > 0 && !AllAddrsBlocked(fs, advertised) {
+			reason += " while some advertised addresses are allowed"
+		}
+		return true, reason
+	}
+	for _, a := range advertised {
+		if fs.AddrBlocked(a) {
+			return true, fmt.Sprintf("advertised address %s blocked by %s", a, blockingRule(fs, a))
+		}
+	}
+	return false, ""
+}
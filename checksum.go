@@ -0,0 +1,29 @@
+package filter
+
+import (
+	"crypto/sha256"
+	"encoding/hex"
+	"fmt"
+	"math/big"
+)
+
+// Checksum returns a hex-encoded hash of the Filters' default action, rules
+// and verdicts. Any change to the default or the rules changes the checksum.
+// Filters does not expose the order of rules with different actions, so the
+// verdict reached over every region of the address space is hashed too: a
+// reordering that changes how any address is treated, such as removing and
+// re-adding a rule, changes the checksum as well. This makes it a cheap way
+// for tests to assert that a shared set was not mutated.
+func Checksum(fs *Filters) string {
+	h := sha256.New()
+	fmt.Fprintf(h, "default %s\n", actionName(fs.DefaultAction))
+	for _, r := range Rules(fs) {
+		fmt.Fprintf(h, "%s %s\n", actionName(r.Action), ruleString(r.Net))
+	}
+	for _, size := range families {
+		walkRegions(fs, size, func(lo, _ *big.Int, _, blocked bool) {
+			fmt.Fprintf(h, "region %s %t\n", intToIP(lo, size), blocked)
+		})
+	}
+	return hex.EncodeToString(h.Sum(nil))
+}
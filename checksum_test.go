@@ -0,0 +1,43 @@
+package filter
+
+import (
+	"net"
+	"testing"
+
+	"github.com/multiformats/go-multiaddr"
+)
+
+func TestChecksumSeesReordering(t *testing.T) {
+	fs := filtersFromRules(t, ActionAccept,
+		testRule{"10.0.0.0/8", ActionDeny},
+		testRule{"10.1.0.0/16", ActionAccept},
+	)
+	a := multiaddr.StringCast("/ip4/10.1.0.1/tcp/1")
+	if fs.AddrBlocked(a) {
+		t.Fatalf("%s should be accepted", a)
+	}
+	before := Checksum(fs)
+	if Checksum(fs) != before {
+		t.Fatal("checksum is not stable")
+	}
+
+	// Re-adding the /8 moves it after the /16 accept rule.
+	_, ipnet, _ := net.ParseCIDR("10.0.0.0/8")
+	fs.RemoveLiteral(*ipnet)
+	fs.AddFilter(*ipnet, ActionDeny)
+	if !fs.AddrBlocked(a) {
+		t.Fatalf("%s should now be denied", a)
+	}
+	if Checksum(fs) == before {
+		t.Fatal("checksum did not change although verdicts did")
+	}
+}
+
+func TestChecksumDefault(t *testing.T) {
+	fs := NewFilters()
+	before := Checksum(fs)
+	fs.DefaultAction = ActionDeny
+	if Checksum(fs) == before {
+		t.Fatal("checksum did not change with the default action")
+	}
+}
@@ -0,0 +1,95 @@
+package filter
+
+import (
+	"fmt"
+	"math/big"
+	"net"
+	"strings"
+)
+
+// Covers returns true if outer fully contains inner, that is, if every IP in
+// inner is also in outer. IPv4 networks written in their IPv4-mapped IPv6
+// form, such as ::ffff:10.0.0.0/104, are treated as the IPv4 networks they
+// match. Networks with non-canonical masks cover nothing.
+func Covers(outer, inner *net.IPNet) bool {
+	outerSize, outerLo, outerHi, ok := netSpan(*outer)
+	if !ok {
+		return false
+	}
+	innerSize, innerLo, innerHi, ok := netSpan(*inner)
+	if !ok || outerSize != innerSize {
+		return false
+	}
+	return outerLo.Cmp(innerLo) <= 0 && innerHi.Cmp(outerHi) <= 0
+}
+
+// ParseIPRange parses an inclusive IP range of the form "start-end", such as
+// "1.2.3.0-1.2.3.255", and returns the minimal list of CIDRs covering
+// exactly that range. Both ends must be of the same address family.
+func ParseIPRange(s string) ([]*net.IPNet, error) {
+	parts := strings.SplitN(s, "-", 2)
+	if len(parts) != 2 {
+		return nil, fmt.Errorf("invalid IP range %q", s)
+	}
+	start := net.ParseIP(strings.TrimSpace(parts[0]))
+	end := net.ParseIP(strings.TrimSpace(parts[1]))
+	if start == nil || end == nil {
+		return nil, fmt.Errorf("invalid IP range %q", s)
+	}
+
+	size := net.IPv6len
+	if start4, end4 := start.To4(), end.To4(); start4 != nil || end4 != nil {
+		if start4 == nil || end4 == nil {
+			return nil, fmt.Errorf("invalid IP range %q: mixed address families", s)
+		}
+		start, end, size = start4, end4, net.IPv4len
+	}
+
+	lo, hi := ipToInt(start), ipToInt(end)
+	if lo.Cmp(hi) > 0 {
+		return nil, fmt.Errorf("invalid IP range %q: start is after end", s)
+	}
+	return rangeToCIDRs(lo, hi, size), nil
+}
+
+// rangeToCIDRs returns the minimal list of CIDRs covering the inclusive range
+// [lo, hi] of addresses size bytes long.
+func rangeToCIDRs(lo, hi *big.Int, size int) []*net.IPNet {
+	bits := 8 * size
+	one := big.NewInt(1)
+
+	var nets []*net.IPNet
+	cur := new(big.Int).Set(lo)
+	for cur.Cmp(hi) <= 0 {
+		// Grow the block for as long as it stays aligned on cur and
+		// doesn't extend past hi.
+		host := 0
+		for host < bits && cur.Bit(host) == 0 {
+			last := new(big.Int).Lsh(one, uint(host+1))
+			last.Add(last, cur).Sub(last, one)
+			if last.Cmp(hi) > 0 {
+				break
+			}
+			host++
+		}
+
+		nets = append(nets, &net.IPNet{
+			IP:   intToIP(cur, size),
+			Mask: net.CIDRMask(bits-host, bits),
+		})
+		cur.Add(cur, new(big.Int).Lsh(one, uint(host)))
+	}
+	return nets
+}
+
+func ipToInt(ip net.IP) *big.Int {
+	return new(big.Int).SetBytes(ip)
+}
+
+// intToIP converts x into an IP address size bytes long.
+func intToIP(x *big.Int, size int) net.IP {
+	b := x.Bytes()
+	ip := make(net.IP, size)
+	copy(ip[size-len(b):], b)
+	return ip
+}
@@ -0,0 +1,96 @@
+package filter
+
+import (
+	"net"
+	"testing"
+)
+
+func TestCovers(t *testing.T) {
+	for _, tc := range []struct {
+		outer, inner string
+		covers       bool
+	}{
+		{"10.0.0.0/8", "10.1.0.0/16", true},
+		{"10.0.0.0/8", "10.0.0.0/8", true},
+		{"10.1.0.0/16", "10.0.0.0/8", false},
+		{"10.0.0.0/8", "11.0.0.0/16", false},
+		{"10.0.0.0/8", "::ffff:10.1.0.0/112", true},
+		{"::ffff:10.0.0.0/104", "10.1.0.0/16", true},
+		{"::ffff:0:0/96", "10.1.0.0/16", true},
+		{"::/0", "10.1.0.0/16", false},
+		{"0.0.0.0/0", "2001:db8::/32", false},
+		{"2001:db8::/32", "2001:db8:1::/48", true},
+	} {
+		_, outer, _ := net.ParseCIDR(tc.outer)
+		_, inner, _ := net.ParseCIDR(tc.inner)
+		if got := Covers(outer, inner); got != tc.covers {
+			t.Errorf("Covers(%s, %s) = %v, want %v", tc.outer, tc.inner, got, tc.covers)
+		}
+	}
+}
+
+func TestWouldConflictMapped(t *testing.T) {
+	fs := NewFilters()
+	_, accept, _ := net.ParseCIDR("10.0.0.0/8")
+	fs.AddFilter(*accept, ActionAccept)
+
+	_, mapped, _ := net.ParseCIDR("::ffff:10.1.0.0/112")
+	if got := Overlapping(fs, mapped); len(got) != 1 {
+		t.Fatalf("expected 10.0.0.0/8 to overlap %s, got %v", mapped, got)
+	}
+	if got := WouldConflict(fs, mapped, true); len(got) != 1 {
+		t.Fatalf("expected a deny of %s to conflict with 10.0.0.0/8, got %v", mapped, got)
+	}
+}
+
+func TestParseIPRange(t *testing.T) {
+	for _, tc := range []struct {
+		in   string
+		want []string
+	}{
+		{"1.2.3.0-1.2.3.255", []string{"1.2.3.0/24"}},
+		{"1.2.3.1-1.2.3.6", []string{"1.2.3.1/32", "1.2.3.2/31", "1.2.3.4/31", "1.2.3.6/32"}},
+		{"1.2.3.4-1.2.3.4", []string{"1.2.3.4/32"}},
+		{"0.0.0.0-255.255.255.255", []string{"0.0.0.0/0"}},
+		{"::-ffff:ffff:ffff:ffff:ffff:ffff:ffff:ffff", []string{"::/0"}},
+		{"2001:db8::-2001:db8::1:0", []string{"2001:db8::/112", "2001:db8::1:0/128"}},
+		{" 1.2.3.0 - 1.2.3.127 ", []string{"1.2.3.0/25"}},
+	} {
+		nets, err := ParseIPRange(tc.in)
+		if err != nil {
+			t.Errorf("ParseIPRange(%q): %s", tc.in, err)
+			continue
+		}
+		if got := netStrings(nets); !equalStrings(got, tc.want) {
+			t.Errorf("ParseIPRange(%q) = %v, want %v", tc.in, got, tc.want)
+		}
+	}
+}
+
+func TestParseIPRangeErrors(t *testing.T) {
+	for _, in := range []string{
+		"",
+		"1.2.3.4",
+		"1.2.3.4-",
+		"1.2.3.4-1.2.3.x",
+		"1.2.3.6-1.2.3.1",
+		"1.2.3.4-::1",
+		"::1-1.2.3.4",
+	} {
+		if nets, err := ParseIPRange(in); err == nil {
+			t.Errorf("ParseIPRange(%q) = %v, want an error", in, nets)
+		}
+	}
+}
+
+func equalStrings(a, b []string) bool {
+	if len(a) != len(b) {
+		return false
+	}
+	for i := range a {
+		if a[i] != b[i] {
+			return false
+		}
+	}
+	return true
+}
@@ -0,0 +1,46 @@
+package filter
+
+import (
+	"errors"
+	"fmt"
+	"net"
+	"strings"
+)
+
+// FiltersFromConfig builds a Filters set from lists of CIDRs to accept and
+// deny, as commonly stored in application configs. If defaultReject is set,
+// addresses not matching any rule are denied.
+//
+// Deny rules are added before accept rules, so accept rules carve exceptions
+// out of overlapping deny rules. All invalid entries are reported in the
+// returned error.
+func FiltersFromConfig(allow, deny []string, defaultReject bool) (*Filters, error) {
+	fs := NewFilters()
+	if defaultReject {
+		fs.DefaultAction = ActionDeny
+	}
+
+	var problems []string
+	for _, list := range []struct {
+		name   string
+		cidrs  []string
+		action Action
+	}{
+		{"deny", deny, ActionDeny},
+		{"allow", allow, ActionAccept},
+	} {
+		for _, cidr := range list.cidrs {
+			_, ipnet, err := net.ParseCIDR(strings.TrimSpace(cidr))
+			if err != nil {
+				problems = append(problems, fmt.Sprintf("%s: invalid CIDR %q", list.name, cidr))
+				continue
+			}
+			fs.AddFilter(*ipnet, list.action)
+		}
+	}
+
+	if len(problems) > 0 {
+		return nil, errors.New(strings.Join(problems, "; "))
+	}
+	return fs, nil
+}
@@ -0,0 +1,32 @@
+package filter
+
+import (
+	"fmt"
+	"net"
+)
+
+// WrapConn checks the remote address of an accepted connection against the
+// Filters set. If the address is denied, the connection is closed and an
+// error is returned; otherwise the connection is returned unchanged.
+//
+// Remote addresses that carry no IP, such as unix sockets, are subject to the
+// default policy.
+func WrapConn(fs *Filters, c net.Conn) (net.Conn, error) {
+	var blocked bool
+	switch addr := c.RemoteAddr().(type) {
+	case *net.TCPAddr:
+		blocked = IPBlocked(fs, addr.IP)
+	case *net.UDPAddr:
+		blocked = IPBlocked(fs, addr.IP)
+	case *net.IPAddr:
+		blocked = IPBlocked(fs, addr.IP)
+	default:
+		blocked = fs.DefaultAction == ActionDeny
+	}
+
+	if blocked {
+		c.Close()
+		return nil, fmt.Errorf("connection from %s blocked", c.RemoteAddr())
+	}
+	return c, nil
+}
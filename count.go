@@ -0,0 +1,51 @@
+package filter
+
+import (
+	"math/big"
+	"net"
+)
+
+// DeniedAddressCount returns the number of IPv4 and IPv6 addresses denied by
+// an explicit deny rule of the Filters set. Overlapping deny rules are only
+// counted once, and addresses that accept rules carve out of a deny rule are
+// excluded. Addresses only denied by the default policy are not counted.
+func DeniedAddressCount(fs *Filters) *big.Int {
+	total := new(big.Int)
+	for _, size := range families {
+		total.Add(total, deniedInFamily(fs, size))
+	}
+	return total
+}
+
+// deniedInFamily counts the addresses of one family denied by an explicit
+// deny rule.
+func deniedInFamily(fs *Filters, size int) *big.Int {
+	count := new(big.Int)
+	walkRegions(fs, size, func(lo, hi *big.Int, covered, blocked bool) {
+		if covered && blocked {
+			count.Add(count, new(big.Int).Sub(hi, lo))
+		}
+	})
+	return count
+}
+
+// IPv4DenyCoverage returns the fraction, between 0 and 1, of the IPv4
+// address space denied by an explicit deny rule, counted as in
+// DeniedAddressCount.
+func IPv4DenyCoverage(fs *Filters) float64 {
+	denied := deniedInFamily(fs, net.IPv4len)
+	f, _ := new(big.Rat).SetFrac(denied, new(big.Int).Lsh(big.NewInt(1), 8*net.IPv4len)).Float64()
+	return f
+}
+
+// IPv6DenyCoverage returns the fraction of the IPv6 address space denied by
+// an explicit deny rule, counted as in DeniedAddressCount. IPv4-mapped
+// addresses (::ffff:0:0/96), which Filters matches as IPv4, are excluded
+// from the address space. The fraction is returned as a big.Rat, since
+// meaningful IPv6 coverages are usually too small for a float64.
+func IPv6DenyCoverage(fs *Filters) *big.Rat {
+	denied := deniedInFamily(fs, net.IPv6len)
+	space := new(big.Int).Lsh(big.NewInt(1), 8*net.IPv6len)
+	space.Sub(space, new(big.Int).Lsh(big.NewInt(1), 8*net.IPv4len))
+	return new(big.Rat).SetFrac(denied, space)
+}
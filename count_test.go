@@ -0,0 +1,100 @@
+package filter
+
+import (
+	"math/big"
+	"net"
+	"testing"
+)
+
+type testRule struct {
+	cidr   string
+	action Action
+}
+
+func filtersFromRules(t testing.TB, def Action, rules ...testRule) *Filters {
+	fs := NewFilters()
+	fs.DefaultAction = def
+	for _, r := range rules {
+		_, ipnet, err := net.ParseCIDR(r.cidr)
+		if err != nil {
+			t.Fatal(err)
+		}
+		fs.AddFilter(*ipnet, r.action)
+	}
+	return fs
+}
+
+func pow2(n uint) *big.Int {
+	return new(big.Int).Lsh(big.NewInt(1), n)
+}
+
+func TestDeniedAddressCount(t *testing.T) {
+	for _, tc := range []struct {
+		name  string
+		def   Action
+		rules []testRule
+		want  *big.Int
+	}{
+		{"empty", ActionDeny, nil, new(big.Int)},
+		{"overlapping", ActionAccept, []testRule{
+			{"10.0.0.0/8", ActionDeny},
+			{"10.1.0.0/16", ActionDeny},
+		}, pow2(24)},
+		{"carve-out", ActionAccept, []testRule{
+			{"10.0.0.0/8", ActionDeny},
+			{"10.1.0.0/16", ActionAccept},
+		}, new(big.Int).Sub(pow2(24), pow2(16))},
+		{"carve-out overridden", ActionAccept, []testRule{
+			{"10.1.0.0/16", ActionAccept},
+			{"10.0.0.0/8", ActionDeny},
+		}, pow2(24)},
+		{"none carve-out", ActionDeny, []testRule{
+			{"10.0.0.0/8", ActionDeny},
+			{"10.1.0.0/16", ActionNone},
+		}, new(big.Int).Sub(pow2(24), pow2(16))},
+		{"mapped", ActionAccept, []testRule{
+			{"::ffff:10.0.0.0/104", ActionDeny},
+			{"10.0.0.0/8", ActionDeny},
+			{"::ffff:10.1.0.0/112", ActionAccept},
+		}, new(big.Int).Sub(pow2(24), pow2(16))},
+		{"ipv6", ActionAccept, []testRule{
+			{"2001:db8::/32", ActionDeny},
+			{"1.2.3.0/24", ActionDeny},
+		}, new(big.Int).Add(pow2(96), pow2(8))},
+		{"ipv6 everything", ActionAccept, []testRule{
+			{"::/0", ActionDeny},
+		}, new(big.Int).Sub(pow2(128), pow2(32))},
+	} {
+		t.Run(tc.name, func(t *testing.T) {
+			fs := filtersFromRules(t, tc.def, tc.rules...)
+			if got := DeniedAddressCount(fs); got.Cmp(tc.want) != 0 {
+				t.Fatalf("DeniedAddressCount = %s, want %s", got, tc.want)
+			}
+		})
+	}
+}
+
+func TestDenyCoverage(t *testing.T) {
+	fs := filtersFromRules(t, ActionDeny,
+		testRule{"0.0.0.0/1", ActionDeny},
+		testRule{"64.0.0.0/2", ActionAccept},
+		testRule{"::ffff:128.0.0.0/98", ActionDeny},
+		testRule{"2001:db8::/32", ActionDeny},
+	)
+
+	if got := IPv4DenyCoverage(fs); got != 0.5 {
+		t.Errorf("IPv4DenyCoverage = %v, want 0.5", got)
+	}
+	want := new(big.Rat).SetFrac(pow2(96), new(big.Int).Sub(pow2(128), pow2(32)))
+	if got := IPv6DenyCoverage(fs); got.Cmp(want) != 0 {
+		t.Errorf("IPv6DenyCoverage = %s, want %s", got, want)
+	}
+}
+
+func BenchmarkDeniedAddressCount(b *testing.B) {
+	fs := GenerateFilters(2000, 1)
+	b.ResetTimer()
+	for i := 0; i < b.N; i++ {
+		DeniedAddressCount(fs)
+	}
+}
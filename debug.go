@@ -0,0 +1,72 @@
+package filter
+
+import (
+	"encoding/json"
+	"net/http"
+)
+
+type debugRule struct {
+	CIDR   string `json:"cidr"`
+	Action string `json:"action"`
+}
+
+type debugState struct {
+	Enabled       bool        `json:"enabled"`
+	DefaultAction string      `json:"default_action,omitempty"`
+	Rules         []debugRule `json:"rules"`
+	ActiveGuards  []string    `json:"active_guards"`
+}
+
+// DebugHandler returns an http.Handler that serves the current state of the
+// Filterer as JSON on GET: every rule, grouped by action as in Rules, and
+// whether a *GuardedFilters is enabled and which of its guards are on. The default action is included
+// for *Filters and the wrappers in this package, which expose the Filters
+// they wrap.
+func DebugHandler(f Filterer) http.Handler {
+	return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if r.Method != http.MethodGet {
+			w.Header().Set("Allow", http.MethodGet)
+			http.Error(w, "method not allowed", http.StatusMethodNotAllowed)
+			return
+		}
+
+		state := debugState{
+			Enabled:      true,
+			Rules:        []debugRule{},
+			ActiveGuards: []string{},
+		}
+		if fs := underlyingFilters(f); fs != nil {
+			state.DefaultAction = actionName(fs.DefaultAction)
+		}
+		if gf, ok := f.(*GuardedFilters); ok {
+			state.Enabled = gf.Enabled
+			state.ActiveGuards = append(state.ActiveGuards, gf.ActiveGuards()...)
+		}
+		for _, action := range actions {
+			for _, ipnet := range f.FiltersForAction(action) {
+				state.Rules = append(state.Rules, debugRule{
+					CIDR:   ruleString(ipnet),
+					Action: actionName(action),
+				})
+			}
+		}
+
+		w.Header().Set("Content-Type", "application/json")
+		json.NewEncoder(w).Encode(state)
+	})
+}
+
+// underlyingFilters returns the Filters set behind f, or nil if f is not
+// one of the implementations in this package.
+func underlyingFilters(f Filterer) *Filters {
+	switch f := f.(type) {
+	case *Filters:
+		return f
+	case *RateLimitedFilters:
+		return f.Filters
+	case *GuardedFilters:
+		return f.Filters
+	default:
+		return nil
+	}
+}
@@ -0,0 +1,58 @@
+package filter
+
+import (
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"reflect"
+	"testing"
+)
+
+func TestDebugHandler(t *testing.T) {
+	fs := filtersFromRules(t, ActionDeny,
+		testRule{"10.0.0.0/8", ActionAccept},
+		testRule{"10.1.0.0/16", ActionDeny},
+	)
+	gf := NewGuarded(fs)
+	gf.RejectMulticast = true
+
+	for _, tc := range []struct {
+		name   string
+		f      Filterer
+		guards []string
+	}{
+		{"filters", fs, []string{}},
+		{"guarded", gf, []string{"reject-multicast"}},
+	} {
+		t.Run(tc.name, func(t *testing.T) {
+			rec := httptest.NewRecorder()
+			DebugHandler(tc.f).ServeHTTP(rec, httptest.NewRequest(http.MethodGet, "/", nil))
+			if rec.Code != http.StatusOK {
+				t.Fatalf("status %d", rec.Code)
+			}
+
+			var state debugState
+			if err := json.Unmarshal(rec.Body.Bytes(), &state); err != nil {
+				t.Fatal(err)
+			}
+			want := debugState{
+				Enabled:       true,
+				DefaultAction: "deny",
+				Rules: []debugRule{
+					{"10.0.0.0/8", "accept"},
+					{"10.1.0.0/16", "deny"},
+				},
+				ActiveGuards: tc.guards,
+			}
+			if !reflect.DeepEqual(state, want) {
+				t.Fatalf("got %+v, want %+v", state, want)
+			}
+		})
+	}
+
+	rec := httptest.NewRecorder()
+	DebugHandler(fs).ServeHTTP(rec, httptest.NewRequest(http.MethodPost, "/", nil))
+	if rec.Code != http.StatusMethodNotAllowed {
+		t.Fatalf("POST: status %d", rec.Code)
+	}
+}
@@ -0,0 +1,83 @@
+package filter
+
+import (
+	"net"
+
+	"github.com/multiformats/go-multiaddr"
+)
+
+// Decision describes the verdict reached by a Filters set for an address,
+// along with how that verdict was reached.
+type Decision int
+
+const (
+	// DecisionUnknown is the zero Decision; Decide never returns it.
+	DecisionUnknown Decision = iota
+	// DecisionAllow means an explicit accept rule matched the address.
+	DecisionAllow
+	// DecisionDeny means an explicit deny rule matched the address.
+	DecisionDeny
+	// DecisionDefaultedAllow means no rule matched and the default policy
+	// accepted the address.
+	DecisionDefaultedAllow
+	// DecisionDefaultedDeny means no rule matched and the default policy
+	// denied the address.
+	DecisionDefaultedDeny
+	// DecisionUnparseable means no IP could be extracted from the address.
+	// Filters.AddrBlocked applies the default policy to such addresses.
+	DecisionUnparseable
+)
+
+func (d Decision) String() string {
+	switch d {
+	case DecisionAllow:
+		return "allow"
+	case DecisionDeny:
+		return "deny"
+	case DecisionDefaultedAllow:
+		return "defaulted-allow"
+	case DecisionDefaultedDeny:
+		return "defaulted-deny"
+	case DecisionUnparseable:
+		return "unparseable"
+	default:
+		return "unknown"
+	}
+}
+
+// Decide evaluates the address against the Filters set, reporting both the
+// verdict and whether it came from an explicit rule or the default policy.
+func Decide(fs *Filters, a multiaddr.Multiaddr) Decision {
+	ip, found := addrIP(a)
+	if !found {
+		return DecisionUnparseable
+	}
+
+	blocked := fs.AddrBlocked(a)
+	if matchesAny(fs, ip) {
+		if blocked {
+			return DecisionDeny
+		}
+		return DecisionAllow
+	}
+	if blocked {
+		return DecisionDefaultedDeny
+	}
+	return DecisionDefaultedAllow
+}
+
+// actions lists every Action a filter entry can carry.
+var actions = []Action{ActionNone, ActionAccept, ActionDeny}
+
+// matchesAny returns true if any rule, regardless of its action, contains the
+// IP.
+func matchesAny(fs *Filters, ip net.IP) bool {
+	for _, action := range actions {
+		for _, ipnet := range fs.FiltersForAction(action) {
+			if ipnet.Contains(ip) {
+				return true
+			}
+		}
+	}
+	return false
+}
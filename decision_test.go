@@ -0,0 +1,36 @@
+package filter
+
+import (
+	"testing"
+
+	"github.com/multiformats/go-multiaddr"
+)
+
+func TestDecide(t *testing.T) {
+	fs := filtersFromRules(t, ActionDeny,
+		testRule{"10.0.0.0/8", ActionAccept},
+		testRule{"10.1.0.0/16", ActionDeny},
+	)
+
+	for addr, want := range map[string]Decision{
+		"/ip4/10.0.0.1/tcp/1":     DecisionAllow,
+		"/ip4/10.1.0.1/tcp/1":     DecisionDeny,
+		"/ip4/192.168.0.1/tcp/1":  DecisionDefaultedDeny,
+		"/unix/tmp/socket":        DecisionUnparseable,
+		"/dns4/example.com/tcp/1": DecisionUnparseable,
+	} {
+		if got := Decide(fs, multiaddr.StringCast(addr)); got != want {
+			t.Errorf("Decide(%s) = %s, want %s", addr, got, want)
+		}
+	}
+
+	fs.DefaultAction = ActionAccept
+	if got := Decide(fs, multiaddr.StringCast("/ip4/192.168.0.1/tcp/1")); got != DecisionDefaultedAllow {
+		t.Errorf("expected %s, got %s", DecisionDefaultedAllow, got)
+	}
+
+	var zero Decision
+	if zero != DecisionUnknown || zero.String() != "unknown" {
+		t.Errorf("zero Decision is %s", zero)
+	}
+}
@@ -0,0 +1,48 @@
+package filter
+
+// FilterDiff describes the rule changes between two Filters sets.
+type FilterDiff struct {
+	// Added lists rules present only in the new set.
+	Added []Rule
+	// Removed lists rules present only in the old set.
+	Removed []Rule
+	// Changed lists rules present in both sets with a different action,
+	// carrying the action of the new set.
+	Changed []Rule
+	// BehaviorChanged is true if the two sets reach a different verdict
+	// for some address. It can be true with no rule changes listed, as
+	// Filters does not expose the order of rules with different actions,
+	// and reordering them may change verdicts.
+	BehaviorChanged bool
+}
+
+// Diff compares the rules of fs against those of other, treating other as
+// the proposed new set. Rules are identified by their network.
+func Diff(fs, other *Filters) FilterDiff {
+	oldRules := Rules(fs)
+	old := make(map[string]Action, len(oldRules))
+	for _, r := range oldRules {
+		old[r.Net.String()] = r.Action
+	}
+
+	d := FilterDiff{BehaviorChanged: !Equivalent(fs, other)}
+	seen := make(map[string]struct{})
+	for _, r := range Rules(other) {
+		key := r.Net.String()
+		seen[key] = struct{}{}
+
+		action, ok := old[key]
+		switch {
+		case !ok:
+			d.Added = append(d.Added, r)
+		case action != r.Action:
+			d.Changed = append(d.Changed, r)
+		}
+	}
+	for _, r := range oldRules {
+		if _, ok := seen[r.Net.String()]; !ok {
+			d.Removed = append(d.Removed, r)
+		}
+	}
+	return d
+}
@@ -0,0 +1,53 @@
+package filter
+
+import "testing"
+
+func TestDiff(t *testing.T) {
+	old := filtersFromRules(t, ActionAccept,
+		testRule{"10.0.0.0/8", ActionDeny},
+		testRule{"10.1.0.0/16", ActionAccept},
+		testRule{"192.168.0.0/16", ActionDeny},
+	)
+	proposed := filtersFromRules(t, ActionAccept,
+		testRule{"10.0.0.0/8", ActionDeny},
+		testRule{"10.1.0.0/16", ActionDeny},
+		testRule{"172.16.0.0/12", ActionDeny},
+	)
+
+	d := Diff(old, proposed)
+	if len(d.Added) != 1 || d.Added[0].Net.String() != "172.16.0.0/12" {
+		t.Errorf("Added = %v", d.Added)
+	}
+	if len(d.Removed) != 1 || d.Removed[0].Net.String() != "192.168.0.0/16" {
+		t.Errorf("Removed = %v", d.Removed)
+	}
+	if len(d.Changed) != 1 || d.Changed[0].Net.String() != "10.1.0.0/16" || d.Changed[0].Action != ActionDeny {
+		t.Errorf("Changed = %v", d.Changed)
+	}
+	if !d.BehaviorChanged {
+		t.Error("BehaviorChanged should be set")
+	}
+
+	if d := Diff(old, old); d.BehaviorChanged || len(d.Added)+len(d.Removed)+len(d.Changed) != 0 {
+		t.Errorf("diff against itself = %+v", d)
+	}
+}
+
+func TestDiffReorderOnly(t *testing.T) {
+	old := filtersFromRules(t, ActionAccept,
+		testRule{"10.0.0.0/8", ActionDeny},
+		testRule{"10.1.0.0/16", ActionAccept},
+	)
+	reordered := filtersFromRules(t, ActionAccept,
+		testRule{"10.1.0.0/16", ActionAccept},
+		testRule{"10.0.0.0/8", ActionDeny},
+	)
+
+	d := Diff(old, reordered)
+	if len(d.Added)+len(d.Removed)+len(d.Changed) != 0 {
+		t.Errorf("expected no rule changes, got %+v", d)
+	}
+	if !d.BehaviorChanged {
+		t.Error("reordering changed verdicts, but BehaviorChanged is not set")
+	}
+}
@@ -0,0 +1,12 @@
+// Package filter provides helpers for filtering multiaddrs by IP network,
+// built on the Filters type of go-multiaddr, which it aliases.
+//
+// Filters guards its rules with a lock it does not expose. Each call into a
+// Filters method takes that lock on its own, so helpers in this package that
+// make several calls, such as Invert or RemoveAll, are not atomic: concurrent
+// queries may observe the set partway through the change.
+//
+// DefaultAction is a plain field that Filters.AddrBlocked reads without
+// holding the lock. Set it, directly or through Invert or ResetToDefaults,
+// before the set is shared between goroutines.
+package filter
@@ -0,0 +1,59 @@
+package filter
+
+import (
+	"fmt"
+	"net"
+)
+
+const (
+	// maxEnumerateBits4 and maxEnumerateBits6 bound the number of host bits
+	// of a block BlockedIPsIn is willing to enumerate.
+	maxEnumerateBits4 = 16
+	maxEnumerateBits6 = 24
+)
+
+// BlockedIPsIn enumerates every IP in block and returns those the Filters set
+// would deny. It returns an error if block is larger than a /16 for IPv4 or a
+// /104 for IPv6.
+//
+// This is intended as a verification aid for small ranges.
+func BlockedIPsIn(fs *Filters, block *net.IPNet) ([]net.IP, error) {
+	ones, bits := block.Mask.Size()
+	if bits == 0 {
+		return nil, fmt.Errorf("invalid mask for %s", block)
+	}
+	maxBits := maxEnumerateBits6
+	if bits == 8*net.IPv4len {
+		maxBits = maxEnumerateBits4
+	}
+	if bits-ones > maxBits {
+		return nil, fmt.Errorf("block %s is too large to enumerate", block)
+	}
+
+	var blocked []net.IP
+	ip := block.IP.Mask(block.Mask)
+	for n := 1 << uint(bits-ones); n > 0; n-- {
+		addr, err := ipMultiaddr(ip)
+		if err != nil {
+			return nil, err
+		}
+		if fs.AddrBlocked(addr) {
+			blocked = append(blocked, ip)
+		}
+		ip = nextIP(ip)
+	}
+	return blocked, nil
+}
+
+// nextIP returns a copy of ip incremented by one, wrapping around at the end
+// of the address space.
+func nextIP(ip net.IP) net.IP {
+	next := append(net.IP(nil), ip...)
+	for i := len(next) - 1; i >= 0; i-- {
+		next[i]++
+		if next[i] != 0 {
+			break
+		}
+	}
+	return next
+}
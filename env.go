@@ -0,0 +1,78 @@
+package filter
+
+import (
+	"fmt"
+	"net"
+	"os"
+	"strings"
+)
+
+// FiltersFromEnv builds a Filters set from the following environment
+// variables:
+//
+//	<prefix>_DENY_CIDRS      comma-separated CIDRs to deny
+//	<prefix>_ALLOW_CIDRS     comma-separated CIDRs to accept
+//	<prefix>_DEFAULT_POLICY  "accept" or "deny" (defaults to "accept")
+//
+// Deny rules are added before accept rules, so accept rules carve exceptions
+// out of overlapping deny rules.
+func FiltersFromEnv(prefix string) (*Filters, error) {
+	fs := NewFilters()
+
+	for _, v := range []struct {
+		name   string
+		action Action
+	}{
+		{prefix + "_DENY_CIDRS", ActionDeny},
+		{prefix + "_ALLOW_CIDRS", ActionAccept},
+	} {
+		nets, err := parseCIDRList(os.Getenv(v.name))
+		if err != nil {
+			return nil, fmt.Errorf("%s: %s", v.name, err)
+		}
+		for _, ipnet := range nets {
+			fs.AddFilter(*ipnet, v.action)
+		}
+	}
+
+	name := prefix + "_DEFAULT_POLICY"
+	if value := os.Getenv(name); value != "" {
+		action, err := parsePolicy(value)
+		if err != nil {
+			return nil, fmt.Errorf("%s: %s", name, err)
+		}
+		fs.DefaultAction = action
+	}
+
+	return fs, nil
+}
+
+// parseCIDRList parses a comma-separated list of CIDRs, ignoring whitespace
+// and empty elements.
+func parseCIDRList(s string) ([]*net.IPNet, error) {
+	var nets []*net.IPNet
+	for _, field := range strings.Split(s, ",") {
+		field = strings.TrimSpace(field)
+		if field == "" {
+			continue
+		}
+		_, ipnet, err := net.ParseCIDR(field)
+		if err != nil {
+			return nil, fmt.Errorf("invalid CIDR %q", field)
+		}
+		nets = append(nets, ipnet)
+	}
+	return nets, nil
+}
+
+// parsePolicy parses a default policy name into an Action.
+func parsePolicy(s string) (Action, error) {
+	switch strings.ToLower(strings.TrimSpace(s)) {
+	case "accept", "allow":
+		return ActionAccept, nil
+	case "deny", "reject":
+		return ActionDeny, nil
+	default:
+		return ActionNone, fmt.Errorf("invalid policy %q", s)
+	}
+}
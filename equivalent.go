@@ -0,0 +1,29 @@
+package filter
+
+// Equivalent returns true if a and b reach the same verdict for every
+// address, even if their rules are written differently. This is an exact
+// check: the address space is split into ranges over which neither set
+// changes its verdict, and each range is compared once.
+func Equivalent(a, b *Filters) bool {
+	if (a.DefaultAction == ActionDeny) != (b.DefaultAction == ActionDeny) {
+		// Addresses without an IP get the default policy.
+		return false
+	}
+
+	rules := append(allRules(a), allRules(b)...)
+	for _, size := range families {
+		points, mapped := familyRegions(rules, size)
+		for i := 0; i < len(points)-1; i++ {
+			// IPv4-mapped addresses are matched as IPv4, which the
+			// IPv4 pass already covers.
+			if mapped(points[i]) {
+				continue
+			}
+			ip := intToIP(points[i], size)
+			if IPBlocked(a, ip) != IPBlocked(b, ip) {
+				return false
+			}
+		}
+	}
+	return true
+}
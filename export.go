@@ -0,0 +1,96 @@
+package filter
+
+import (
+	"fmt"
+	"io"
+)
+
+// WriteDenyList writes the CIDRs of all deny rules to w, one per line, in
+// rule order. A rule whose action was changed keeps the position it was
+// first added at. As only the deny rules are written, their order relative
+// to accept rules, which decides the verdict where rules overlap, is lost.
+func WriteDenyList(fs *Filters, w io.Writer) error {
+	return writeList(fs, w, ActionDeny)
+}
+
+// WriteAllowList writes the CIDRs of all accept rules to w, one per line, in
+// rule order. As with WriteDenyList, their order relative to deny rules is
+// lost.
+func WriteAllowList(fs *Filters, w io.Writer) error {
+	return writeList(fs, w, ActionAccept)
+}
+
+func writeList(fs *Filters, w io.Writer, action Action) error {
+	for _, ipnet := range fs.FiltersForAction(action) {
+		if _, err := fmt.Fprintln(w, ipnet.String()); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// DenyCIDRStrings returns the canonical CIDR strings of all deny rules, in
+// rule order, with the same caveats as WriteDenyList: a rule whose action was
+// changed keeps its original position, and the order relative to accept
+// rules is lost.
+func DenyCIDRStrings(fs *Filters) []string {
+	return cidrStrings(fs, ActionDeny)
+}
+
+// AllowCIDRStrings returns the canonical CIDR strings of all accept rules, in
+// rule order. Their order relative to deny rules is lost.
+func AllowCIDRStrings(fs *Filters) []string {
+	return cidrStrings(fs, ActionAccept)
+}
+
+func cidrStrings(fs *Filters, action Action) []string {
+	var cidrs []string
+	for _, ipnet := range fs.FiltersForAction(action) {
+		cidrs = append(cidrs, ipnet.String())
+	}
+	return cidrs
+}
+
+// NullRoutePlatform selects the command syntax ExportNullRoutes renders.
+type NullRoutePlatform int
+
+const (
+	// NullRouteLinux renders iproute2 blackhole routes, e.g.
+	// "ip route add blackhole 10.0.0.0/8".
+	NullRouteLinux NullRoutePlatform = iota
+	// NullRouteBSD renders BSD (and macOS) route(8) blackhole routes, e.g.
+	// "route add -net 10.0.0.0/8 127.0.0.1 -blackhole".
+	NullRouteBSD
+)
+
+// ExportNullRoutes renders every deny rule as a command installing a null
+// route for its network on the given platform, in the order the rules were
+// added. It returns nil for an unknown platform.
+//
+// Null routes only drop traffic to the denied networks; accept rules and the
+// default policy are not represented.
+func ExportNullRoutes(fs *Filters, platform NullRoutePlatform) []string {
+	var routes []string
+	for _, ipnet := range fs.FiltersForAction(ActionDeny) {
+		v4 := ipnet.IP.To4() != nil
+		cidr := ipnet.String()
+
+		switch platform {
+		case NullRouteLinux:
+			if v4 {
+				routes = append(routes, "ip route add blackhole "+cidr)
+			} else {
+				routes = append(routes, "ip -6 route add blackhole "+cidr)
+			}
+		case NullRouteBSD:
+			if v4 {
+				routes = append(routes, "route add -net "+cidr+" 127.0.0.1 -blackhole")
+			} else {
+				routes = append(routes, "route add -inet6 -net "+cidr+" ::1 -blackhole")
+			}
+		default:
+			return nil
+		}
+	}
+	return routes
+}
@@ -0,0 +1,113 @@
+package filter
+
+import "net"
+
+// translationPrefix is an IPv6 prefix whose addresses embed an IPv4 address
+// right after the prefix.
+type translationPrefix struct {
+	prefix net.IPNet
+	// offset is the byte offset of the embedded IPv4 address, which is also
+	// the prefix length in bytes.
+	offset int
+}
+
+// translationPrefixes lists the prefixes FamilyCoverage pairs IPv4 and IPv6
+// rules through: the NAT64 well-known prefix and 6to4.
+var translationPrefixes = []translationPrefix{
+	{net.IPNet{IP: net.ParseIP("64:ff9b::"), Mask: net.CIDRMask(96, 128)}, 12},
+	{net.IPNet{IP: net.ParseIP("2002::"), Mask: net.CIDRMask(16, 128)}, 2},
+}
+
+// FamilyCoverage summarizes the deny rules by address family, to help spot
+// dual-stack policies that block a network over one family but forgot the
+// other. It returns the number of IPv4 and IPv6 deny rules, along with the
+// rules of each family that have no counterpart in the other.
+//
+// IPv4 and IPv6 networks only correspond through address translation, so a
+// rule's counterparts are its translations into the NAT64 well-known prefix
+// (64:ff9b::/96) and the 6to4 prefix (2002::/16). An IPv4 deny rule is
+// reported in onlyV4 unless some deny rule covers one of its translations,
+// and an IPv6 deny rule within one of these prefixes is reported in onlyV6
+// unless some deny rule covers the IPv4 network it embeds. Other IPv6 rules
+// have no IPv4 counterpart and are never reported. Rules written in
+// IPv4-mapped IPv6 form match IPv4 addresses and are counted as IPv4.
+//
+// Only deny rules are compared; accept rules carving exceptions out of a
+// counterpart are not taken into account.
+func FamilyCoverage(fs *Filters) (v4Count, v6Count int, onlyV4, onlyV6 []*net.IPNet) {
+	deny := fs.FiltersForAction(ActionDeny)
+	covered := func(ipnet net.IPNet) bool {
+		for i := range deny {
+			if Covers(&deny[i], &ipnet) {
+				return true
+			}
+		}
+		return false
+	}
+
+	for _, ipnet := range deny {
+		ipnet := ipnet
+		if ipnet.IP.To4() != nil {
+			v4Count++
+			paired := false
+			for _, tp := range translationPrefixes {
+				if t, ok := tp.fromIPv4(ipnet); ok && covered(t) {
+					paired = true
+					break
+				}
+			}
+			if !paired {
+				onlyV4 = append(onlyV4, &ipnet)
+			}
+			continue
+		}
+
+		v6Count++
+		for _, tp := range translationPrefixes {
+			if t, ok := tp.toIPv4(ipnet); ok {
+				if !covered(t) {
+					onlyV6 = append(onlyV6, &ipnet)
+				}
+				break
+			}
+		}
+	}
+	return v4Count, v6Count, onlyV4, onlyV6
+}
+
+// fromIPv4 returns the translation of an IPv4 network into the prefix.
+func (tp translationPrefix) fromIPv4(ipnet net.IPNet) (net.IPNet, bool) {
+	size, _, _, ok := netSpan(ipnet)
+	if !ok || size != net.IPv4len {
+		return net.IPNet{}, false
+	}
+	ones, bits := ipnet.Mask.Size()
+	if bits == 8*net.IPv6len {
+		ones -= 8 * (net.IPv6len - net.IPv4len)
+	}
+	if ones < 0 {
+		ones = 0
+	}
+
+	ip := make(net.IP, net.IPv6len)
+	copy(ip, tp.prefix.IP)
+	copy(ip[tp.offset:], ipnet.IP.To4().Mask(net.CIDRMask(ones, 8*net.IPv4len)))
+	return net.IPNet{IP: ip, Mask: net.CIDRMask(8*tp.offset+ones, 8*net.IPv6len)}, true
+}
+
+// toIPv4 returns the IPv4 network embedded in an IPv6 network within the
+// prefix. IPv6 networks narrower than a translated /32 embed that /32.
+func (tp translationPrefix) toIPv4(ipnet net.IPNet) (net.IPNet, bool) {
+	ones, bits := ipnet.Mask.Size()
+	if bits != 8*net.IPv6len || len(ipnet.IP) != net.IPv6len || ones < 8*tp.offset || !tp.prefix.Contains(ipnet.IP) {
+		return net.IPNet{}, false
+	}
+	ones -= 8 * tp.offset
+	if ones > 8*net.IPv4len {
+		ones = 8 * net.IPv4len
+	}
+
+	mask := net.CIDRMask(ones, 8*net.IPv4len)
+	ip := net.IP(ipnet.IP[tp.offset : tp.offset+net.IPv4len]).Mask(mask)
+	return net.IPNet{IP: ip, Mask: mask}, true
+}
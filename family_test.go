@@ -0,0 +1,46 @@
+package filter
+
+import (
+	"net"
+	"testing"
+)
+
+func TestFamilyCoverage(t *testing.T) {
+	fs := filtersFromRules(t, ActionAccept,
+		// Paired through NAT64.
+		testRule{"10.0.0.0/8", ActionDeny},
+		testRule{"64:ff9b::a00:0/104", ActionDeny},
+		// The /24 is paired through 6to4 by a broader rule, whose
+		// 192.168.0.0/16 is only partly denied over IPv4.
+		testRule{"192.168.1.0/24", ActionDeny},
+		testRule{"2002:c0a8::/32", ActionDeny},
+		// IPv4 only.
+		testRule{"100.64.0.0/10", ActionDeny},
+		testRule{"::ffff:198.18.0.0/111", ActionDeny},
+		// 172.16.0.0/12 has no IPv4 rule.
+		testRule{"64:ff9b::ac10:0/108", ActionDeny},
+		// Native IPv6, which has no IPv4 counterpart.
+		testRule{"2001:db8::/32", ActionDeny},
+		// Accept rules are ignored.
+		testRule{"203.0.113.0/24", ActionAccept},
+	)
+
+	v4Count, v6Count, onlyV4, onlyV6 := FamilyCoverage(fs)
+	if v4Count != 4 || v6Count != 4 {
+		t.Errorf("counts = %d, %d, want 4, 4", v4Count, v6Count)
+	}
+	if got := netStrings(onlyV4); len(got) != 2 || got[0] != "100.64.0.0/10" || got[1] != "198.18.0.0/15" {
+		t.Errorf("onlyV4 = %v", got)
+	}
+	if got := netStrings(onlyV6); len(got) != 2 || got[0] != "2002:c0a8::/32" || got[1] != "64:ff9b::ac10:0/108" {
+		t.Errorf("onlyV6 = %v", got)
+	}
+}
+
+func netStrings(nets []*net.IPNet) []string {
+	var strs []string
+	for _, ipnet := range nets {
+		strs = append(strs, ipnet.String())
+	}
+	return strs
+}
@@ -0,0 +1,158 @@
+package filter
+
+import (
+	"flag"
+	"fmt"
+	"net"
+	"testing"
+
+	"github.com/multiformats/go-multiaddr"
+)
+
+// Filters.AddFilter scans for duplicates, so building a 100k-rule set takes
+// tens of minutes. Those benchmarks only run when asked for.
+var benchLarge = flag.Bool("bench-large", false, "run AddrBlocked benchmarks against 100k rules")
+
+func BenchmarkAddrBlocked(b *testing.B) {
+	for _, n := range []int{10, 1000, 100000} {
+		n := n
+		b.Run(fmt.Sprintf("rules=%d", n), func(b *testing.B) {
+			if n > 10000 && !*benchLarge {
+				b.Skip("pass -bench-large to build a 100k-rule set")
+			}
+
+			fs := GenerateFilters(n, 1)
+			match, nomatch := benchAddrs(b, fs)
+			b.Run("match", func(b *testing.B) {
+				for i := 0; i < b.N; i++ {
+					fs.AddrBlocked(match)
+				}
+			})
+			b.Run("nomatch", func(b *testing.B) {
+				for i := 0; i < b.N; i++ {
+					fs.AddrBlocked(nomatch)
+				}
+			})
+		})
+	}
+}
+
+// benchAddrs returns an address matched by the last deny rule of fs, so
+// that every rule has to be checked, and an address matched by no rule.
+func benchAddrs(b *testing.B, fs *Filters) (match, nomatch multiaddr.Multiaddr) {
+	deny := fs.FiltersForAction(ActionDeny)
+	match, err := ipMultiaddr(deny[len(deny)-1].IP)
+	if err != nil {
+		b.Fatal(err)
+	}
+
+	for ip := net.IPv4(1, 0, 0, 1).To4(); ; ip = nextIP(ip) {
+		if !Mentions(fs, ip) {
+			nomatch, err = ipMultiaddr(ip)
+			if err != nil {
+				b.Fatal(err)
+			}
+			return match, nomatch
+		}
+	}
+}
+
+func TestAddFilterDedupsEquivalentSpellings(t *testing.T) {
+	for _, pair := range [][2]string{
+		{"fd00:0:0::/8", "FD00::/8"},
+		{"::ffff:1.2.3.0/120", "1.2.3.0/24"},
+	} {
+		fs := NewFilters()
+		for _, cidr := range pair {
+			_, ipnet, err := net.ParseCIDR(cidr)
+			if err != nil {
+				t.Fatal(err)
+			}
+			fs.AddFilter(*ipnet, ActionDeny)
+		}
+		if got := fs.FiltersForAction(ActionDeny); len(got) != 1 {
+			t.Errorf("%s and %s: expected 1 rule, got %v", pair[0], pair[1], got)
+		}
+
+		_, ipnet, _ := net.ParseCIDR(pair[1])
+		if !fs.RemoveLiteral(*ipnet) {
+			t.Errorf("%s and %s: RemoveLiteral did not remove the rule", pair[0], pair[1])
+		}
+	}
+}
+
+func TestUnspecifiedAndLoopbackIPv6(t *testing.T) {
+	for _, tc := range []struct {
+		cidr    string
+		addr    string
+		blocked bool
+	}{
+		{"::1/128", "/ip6/::1/tcp/1", true},
+		{"::/128", "/ip6/::/tcp/1", true},
+		{"::/0", "/ip6/::1/tcp/1", true},
+		{"::/0", "/ip6/::/tcp/1", true},
+		{"::/128", "/ip6/::1/tcp/1", false},
+		{"::1/128", "/ip6/::/tcp/1", false},
+		{"127.0.0.0/8", "/ip6/::1/tcp/1", false},
+		{"0.0.0.0/32", "/ip6/::/tcp/1", false},
+		{"0.0.0.0/0", "/ip6/::/tcp/1", false},
+		{"0.0.0.0/0", "/ip6/::1/tcp/1", false},
+		{"::/0", "/ip4/0.0.0.0/tcp/1", false},
+		{"::/0", "/ip4/127.0.0.1/tcp/1", false},
+	} {
+		fs := NewFilters()
+		_, ipnet, _ := net.ParseCIDR(tc.cidr)
+		fs.AddFilter(*ipnet, ActionDeny)
+
+		a := multiaddr.StringCast(tc.addr)
+		if got := fs.AddrBlocked(a); got != tc.blocked {
+			t.Errorf("deny %s: AddrBlocked(%s) = %v, want %v", tc.cidr, a, got, tc.blocked)
+		}
+	}
+}
+
+func TestAddrIPClassification(t *testing.T) {
+	for _, tc := range []struct {
+		addr                  string
+		loopback, unspecified bool
+	}{
+		{"/ip6/::1/tcp/1", true, false},
+		{"/ip6/::/tcp/1", false, true},
+		{"/ip4/127.0.0.1/tcp/1", true, false},
+		{"/ip4/0.0.0.0/tcp/1", false, true},
+		{"/ip6/::ffff:127.0.0.1/tcp/1", true, false},
+	} {
+		ip, ok := addrIP(multiaddr.StringCast(tc.addr))
+		if !ok {
+			t.Fatalf("no IP in %s", tc.addr)
+		}
+		if ip.IsLoopback() != tc.loopback || ip.IsUnspecified() != tc.unspecified {
+			t.Errorf("%s: loopback=%v unspecified=%v, want %v %v", tc.addr, ip.IsLoopback(), ip.IsUnspecified(), tc.loopback, tc.unspecified)
+		}
+	}
+}
+
+func TestIP6ZoneAddress(t *testing.T) {
+	a := multiaddr.StringCast("/ip6zone/eth0/ip6/fe80::1/tcp/1")
+
+	ip, ok := addrIP(a)
+	if !ok || !ip.Equal(net.ParseIP("fe80::1")) {
+		t.Fatalf("addrIP(%s) = %s, %v, want fe80::1", a, ip, ok)
+	}
+
+	fs := NewFilters()
+	_, ipnet, _ := net.ParseCIDR("fe80::/10")
+	fs.AddFilter(*ipnet, ActionDeny)
+	if !fs.AddrBlocked(a) {
+		t.Fatalf("%s not blocked by fe80::/10", a)
+	}
+	if Decide(fs, a) != DecisionDeny {
+		t.Fatalf("Decide(%s) = %s, want deny", a, Decide(fs, a))
+	}
+
+	fs.RemoveLiteral(*ipnet)
+	fs.DefaultAction = ActionDeny
+	if got := Decide(fs, a); got != DecisionDefaultedDeny {
+		t.Fatalf("Decide(%s) = %s, want defaulted-deny", a, got)
+	}
+}
@@ -0,0 +1,32 @@
+package filter
+
+import (
+	"net"
+
+	"github.com/multiformats/go-multiaddr"
+)
+
+// Filterer is the core query and rule-management API of Filters. Code that
+// only needs these operations can accept a Filterer instead of a *Filters,
+// so that tests can inject fakes and applications can provide alternative
+// implementations. *Filters, *RateLimitedFilters and *GuardedFilters
+// implement it.
+type Filterer interface {
+	// AddrBlocked returns true if the address should be denied.
+	AddrBlocked(a multiaddr.Multiaddr) bool
+	// AddFilter adds a rule, or updates the action of an existing rule
+	// for the same network.
+	AddFilter(ipnet net.IPNet, action Action)
+	// RemoveLiteral removes the rule for exactly the given network,
+	// returning whether one was removed.
+	RemoveLiteral(ipnet net.IPNet) bool
+	// FiltersForAction returns the networks of all rules with the
+	// given action.
+	FiltersForAction(action Action) []net.IPNet
+}
+
+var (
+	_ Filterer = (*Filters)(nil)
+	_ Filterer = (*RateLimitedFilters)(nil)
+	_ Filterer = (*GuardedFilters)(nil)
+)
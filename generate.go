@@ -0,0 +1,47 @@
+package filter
+
+import (
+	"math/rand"
+	"net"
+)
+
+// GenerateFilters deterministically builds a Filters set of n distinct rules
+// from the given seed. Roughly three quarters of the rules are IPv4 and one
+// in ten is an accept rule; the rest are denies.
+//
+// It is meant for benchmarking AddrBlocked against reproducible rule sets.
+// Note that Filters.AddFilter scans the existing rules for duplicates, so
+// building very large sets takes time quadratic in n.
+func GenerateFilters(n int, seed int64) *Filters {
+	r := rand.New(rand.NewSource(seed))
+	fs := NewFilters()
+
+	seen := make(map[string]struct{}, n)
+	for len(seen) < n {
+		var ipnet net.IPNet
+		if r.Intn(4) != 0 {
+			ip := make(net.IP, net.IPv4len)
+			r.Read(ip)
+			mask := net.CIDRMask(8+r.Intn(25), 32)
+			ipnet = net.IPNet{IP: ip.Mask(mask), Mask: mask}
+		} else {
+			ip := make(net.IP, net.IPv6len)
+			r.Read(ip)
+			mask := net.CIDRMask(16+r.Intn(113), 128)
+			ipnet = net.IPNet{IP: ip.Mask(mask), Mask: mask}
+		}
+
+		key := ipnet.String()
+		if _, ok := seen[key]; ok {
+			continue
+		}
+		seen[key] = struct{}{}
+
+		action := ActionDeny
+		if r.Intn(10) == 0 {
+			action = ActionAccept
+		}
+		fs.AddFilter(ipnet, action)
+	}
+	return fs
+}
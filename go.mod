@@ -2,4 +2,7 @@ module github.com/libp2p/go-maddr-filter
 
 go 1.13
 
-require github.com/multiformats/go-multiaddr v0.2.2
+require (
+	github.com/multiformats/go-multiaddr v0.2.2
+	golang.org/x/time v0.0.0-20191024005414-555d28b269f0
+)
@@ -0,0 +1,158 @@
+package filter
+
+import (
+	"net"
+
+	"github.com/multiformats/go-multiaddr"
+)
+
+// GuardedFilters wraps a Filters set with flags that accept or reject whole
+// classes of addresses before the rules are consulted. Filters belongs to
+// go-multiaddr and cannot carry these flags itself, so they live on the
+// wrapper instead, in the same way RateLimitedFilters adds rate limiting.
+//
+// GuardedFilters should be created with NewGuarded, which enables it. Guard
+// flags left at their zero value leave the underlying Filters' verdict
+// unchanged.
+type GuardedFilters struct {
+	*Filters
+
+	// Enabled is a kill-switch for the whole set: while it is false,
+	// AddrBlocked accepts every address, ignoring both the guards and the
+	// rules, which are kept as they are.
+	Enabled bool
+
+	// RejectUnspecified blocks dials to 0.0.0.0 and ::.
+	RejectUnspecified bool
+	// RejectMulticast blocks dials to 224.0.0.0/4 and ff00::/8.
+	RejectMulticast bool
+	// RejectLinkLocal blocks dials to link-local unicast (169.254.0.0/16
+	// and fe80::/10) and link-local multicast (224.0.0.0/24 and ff02::/16)
+	// addresses.
+	RejectLinkLocal bool
+	// AllowedProtocols, when not empty, lists the multiaddr protocol codes
+	// an address may dial over, such as multiaddr.P_TCP and
+	// multiaddr.P_QUIC. Addresses that use none of them are blocked,
+	// whatever their IP.
+	AllowedProtocols []int
+	// StrictUnresolved blocks addresses from which no IP can be extracted,
+	// such as /dns4 or /unix addresses, which Filters otherwise subjects to
+	// its default policy.
+	StrictUnresolved bool
+	// AllowOnion accepts /onion and /onion3 addresses, and AllowI2P accepts
+	// /garlic64 and /garlic32 addresses, regardless of the default policy
+	// and StrictUnresolved. They have no IP for the rules to match.
+	AllowOnion bool
+	AllowI2P   bool
+}
+
+// NewGuarded wraps the given Filters, enabled and with all guards off.
+func NewGuarded(fs *Filters) *GuardedFilters {
+	return &GuardedFilters{Filters: fs, Enabled: true}
+}
+
+// ResetToDefaults clears the rules and default policy of the wrapped Filters,
+// as the ResetToDefaults function does, turns every guard off and re-enables
+// the wrapper, leaving it as NewGuarded creates it.
+func (gf *GuardedFilters) ResetToDefaults() {
+	ResetToDefaults(gf.Filters)
+	*gf = *NewGuarded(gf.Filters)
+}
+
+// AddrBlocked returns true if the address is rejected by one of the enabled
+// guards, and otherwise defers to the underlying Filters.
+// Guards are checked before the rules, so an accept rule cannot override
+// them. It returns false for every address while the wrapper is disabled.
+func (gf *GuardedFilters) AddrBlocked(a multiaddr.Multiaddr) bool {
+	if !gf.Enabled {
+		return false
+	}
+	if !gf.protocolAllowed(a) {
+		return true
+	}
+	if gf.anonymityAllowed(a) {
+		return false
+	}
+
+	ip, found := addrIP(a)
+	switch {
+	case !found && gf.StrictUnresolved:
+		return true
+	case found && gf.rejected(ip):
+		return true
+	default:
+		return gf.Filters.AddrBlocked(a)
+	}
+}
+
+// ActiveGuards returns the names of the enabled guards, in the order they
+// are declared, or nil if none are enabled.
+func (gf *GuardedFilters) ActiveGuards() []string {
+	var active []string
+	for _, g := range []struct {
+		name string
+		on   bool
+	}{
+		{"reject-unspecified", gf.RejectUnspecified},
+		{"reject-multicast", gf.RejectMulticast},
+		{"reject-link-local", gf.RejectLinkLocal},
+		{"allowed-protocols", len(gf.AllowedProtocols) > 0},
+		{"strict-unresolved", gf.StrictUnresolved},
+		{"allow-onion", gf.AllowOnion},
+		{"allow-i2p", gf.AllowI2P},
+	} {
+		if g.on {
+			active = append(active, g.name)
+		}
+	}
+	return active
+}
+
+// protocolAllowed returns true if AllowedProtocols is empty or lists any of
+// the address' protocols.
+func (gf *GuardedFilters) protocolAllowed(a multiaddr.Multiaddr) bool {
+	if len(gf.AllowedProtocols) == 0 {
+		return true
+	}
+
+	allowed := false
+	multiaddr.ForEach(a, func(c multiaddr.Component) bool {
+		for _, code := range gf.AllowedProtocols {
+			if c.Protocol().Code == code {
+				allowed = true
+				return false
+			}
+		}
+		return true
+	})
+	return allowed
+}
+
+// anonymityAllowed returns true if the address dials a Tor or I2P network
+// allowed by AllowOnion or AllowI2P.
+func (gf *GuardedFilters) anonymityAllowed(a multiaddr.Multiaddr) bool {
+	allowed := false
+	multiaddr.ForEach(a, func(c multiaddr.Component) bool {
+		switch c.Protocol().Code {
+		case multiaddr.P_ONION, multiaddr.P_ONION3:
+			allowed = gf.AllowOnion
+		case multiaddr.P_GARLIC64, multiaddr.P_GARLIC32:
+			allowed = gf.AllowI2P
+		}
+		return false
+	})
+	return allowed
+}
+
+func (gf *GuardedFilters) rejected(ip net.IP) bool {
+	switch {
+	case gf.RejectUnspecified && ip.IsUnspecified():
+		return true
+	case gf.RejectMulticast && ip.IsMulticast():
+		return true
+	case gf.RejectLinkLocal && (ip.IsLinkLocalUnicast() || ip.IsLinkLocalMulticast()):
+		return true
+	default:
+		return false
+	}
+}
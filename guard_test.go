@@ -0,0 +1,221 @@
+package filter
+
+import (
+	"net"
+	"testing"
+
+	"github.com/multiformats/go-multiaddr"
+)
+
+func TestRejectUnspecified(t *testing.T) {
+	// Accept everything explicitly, so that only the guard can block.
+	fs := NewFilters()
+	for _, cidr := range []string{"0.0.0.0/0", "::/0"} {
+		_, ipnet, _ := net.ParseCIDR(cidr)
+		fs.AddFilter(*ipnet, ActionAccept)
+	}
+	gf := NewGuarded(fs)
+
+	for _, addr := range []string{"/ip4/0.0.0.0/tcp/1", "/ip6/::/tcp/1"} {
+		a := multiaddr.StringCast(addr)
+		if gf.AddrBlocked(a) {
+			t.Errorf("%s blocked with the guard disabled", a)
+		}
+		gf.RejectUnspecified = true
+		if !gf.AddrBlocked(a) {
+			t.Errorf("%s not blocked with RejectUnspecified", a)
+		}
+		gf.RejectUnspecified = false
+	}
+
+	gf.RejectUnspecified = true
+	if a := multiaddr.StringCast("/ip4/1.2.3.4/tcp/1"); gf.AddrBlocked(a) {
+		t.Errorf("%s blocked by RejectUnspecified", a)
+	}
+}
+
+func TestRejectMulticastAndLinkLocal(t *testing.T) {
+	fs := NewFilters()
+	for _, tc := range []struct {
+		addr                 string
+		multicast, linkLocal bool
+	}{
+		{"/ip4/224.0.0.1/tcp/1", true, true},
+		{"/ip4/239.1.2.3/udp/1", true, false},
+		{"/ip6/ff02::1/udp/1", true, true},
+		{"/ip6/ff0e::1/udp/1", true, false},
+		{"/ip4/169.254.1.1/tcp/1", false, true},
+		{"/ip6/fe80::1/tcp/1", false, true},
+		{"/ip6/::ffff:169.254.1.1/tcp/1", false, true},
+		{"/ip4/1.2.3.4/tcp/1", false, false},
+		{"/ip6/2001:db8::1/tcp/1", false, false},
+	} {
+		a := multiaddr.StringCast(tc.addr)
+		gf := NewGuarded(fs)
+		gf.RejectMulticast = true
+		if got := gf.AddrBlocked(a); got != tc.multicast {
+			t.Errorf("RejectMulticast: AddrBlocked(%s) = %v, want %v", a, got, tc.multicast)
+		}
+		gf = NewGuarded(fs)
+		gf.RejectLinkLocal = true
+		if got := gf.AddrBlocked(a); got != tc.linkLocal {
+			t.Errorf("RejectLinkLocal: AddrBlocked(%s) = %v, want %v", a, got, tc.linkLocal)
+		}
+	}
+}
+
+func TestActiveGuards(t *testing.T) {
+	gf := NewGuarded(NewFilters())
+	if got := gf.ActiveGuards(); len(got) != 0 {
+		t.Fatalf("expected no active guards, got %v", got)
+	}
+
+	gf.RejectLinkLocal = true
+	gf.RejectUnspecified = true
+	got := gf.ActiveGuards()
+	if len(got) != 2 || got[0] != "reject-unspecified" || got[1] != "reject-link-local" {
+		t.Fatalf("expected [reject-unspecified reject-link-local], got %v", got)
+	}
+}
+
+func TestGuardedResetToDefaults(t *testing.T) {
+	fs := filtersFromRules(t, ActionDeny, testRule{"10.0.0.0/8", ActionDeny})
+	gf := NewGuarded(fs)
+	gf.RejectUnspecified = true
+	gf.RejectMulticast = true
+	gf.RejectLinkLocal = true
+
+	gf.Enabled = false
+
+	gf.ResetToDefaults()
+	if !gf.Enabled {
+		t.Error("ResetToDefaults did not re-enable the wrapper")
+	}
+	if got := gf.ActiveGuards(); len(got) != 0 {
+		t.Errorf("guards still active: %v", got)
+	}
+	if gf.Filters != fs {
+		t.Error("ResetToDefaults replaced the wrapped Filters")
+	}
+	if n := len(Rules(fs)); n != 0 || fs.DefaultAction != ActionAccept {
+		t.Errorf("Filters not reset: %d rules, default %s", n, actionName(fs.DefaultAction))
+	}
+}
+
+func TestGuardedDisabled(t *testing.T) {
+	fs := filtersFromRules(t, ActionDeny, testRule{"10.0.0.0/8", ActionDeny})
+	gf := NewGuarded(fs)
+	gf.RejectUnspecified = true
+
+	addrs := []multiaddr.Multiaddr{
+		multiaddr.StringCast("/ip4/10.0.0.1/tcp/1"),
+		multiaddr.StringCast("/ip4/1.2.3.4/tcp/1"),
+		multiaddr.StringCast("/ip4/0.0.0.0/tcp/1"),
+		multiaddr.StringCast("/unix/tmp/socket"),
+	}
+	for _, a := range addrs {
+		if !gf.AddrBlocked(a) {
+			t.Errorf("%s not blocked while enabled", a)
+		}
+	}
+
+	gf.Enabled = false
+	for _, a := range addrs {
+		if gf.AddrBlocked(a) {
+			t.Errorf("%s blocked while disabled", a)
+		}
+	}
+	if n := len(Rules(fs)); n != 1 {
+		t.Errorf("disabling changed the rules: %v", Rules(fs))
+	}
+
+	gf.Enabled = true
+	if !gf.AddrBlocked(addrs[0]) {
+		t.Errorf("%s not blocked after re-enabling", addrs[0])
+	}
+}
+
+func TestAllowedProtocols(t *testing.T) {
+	fs := filtersFromRules(t, ActionAccept, testRule{"10.0.0.0/8", ActionDeny})
+	gf := NewGuarded(fs)
+	gf.AllowedProtocols = []int{multiaddr.P_TCP, multiaddr.P_QUIC}
+
+	for addr, blocked := range map[string]bool{
+		"/ip4/1.2.3.4/tcp/1":       false,
+		"/ip4/1.2.3.4/udp/1/quic":  false,
+		"/ip6/2001:db8::1/tcp/1":   false,
+		"/ip4/1.2.3.4/udp/1":       true,
+		"/ip4/1.2.3.4":             true,
+		"/unix/tmp/socket":         true,
+		"/ip4/10.0.0.1/tcp/1":      true,
+		"/ip4/10.0.0.1/udp/1/quic": true,
+	} {
+		a := multiaddr.StringCast(addr)
+		if got := gf.AddrBlocked(a); got != blocked {
+			t.Errorf("AddrBlocked(%s) = %v, want %v", a, got, blocked)
+		}
+	}
+
+	if got := gf.ActiveGuards(); len(got) != 1 || got[0] != "allowed-protocols" {
+		t.Errorf("expected [allowed-protocols], got %v", got)
+	}
+
+	gf.AllowedProtocols = nil
+	if a := multiaddr.StringCast("/ip4/1.2.3.4/udp/1"); gf.AddrBlocked(a) {
+		t.Errorf("%s blocked with no protocol allowlist", a)
+	}
+}
+
+func TestStrictUnresolved(t *testing.T) {
+	gf := NewGuarded(NewFilters())
+	unresolved := []multiaddr.Multiaddr{
+		multiaddr.StringCast("/dns4/example.com/tcp/1"),
+		multiaddr.StringCast("/unix/tmp/socket"),
+	}
+	for _, a := range unresolved {
+		if gf.AddrBlocked(a) {
+			t.Errorf("%s blocked under an accepting default", a)
+		}
+	}
+
+	gf.StrictUnresolved = true
+	for _, a := range unresolved {
+		if !gf.AddrBlocked(a) {
+			t.Errorf("%s not blocked with StrictUnresolved", a)
+		}
+	}
+	if a := multiaddr.StringCast("/ip4/1.2.3.4/tcp/1"); gf.AddrBlocked(a) {
+		t.Errorf("%s blocked by StrictUnresolved", a)
+	}
+}
+
+func TestAllowOnionAndI2P(t *testing.T) {
+	fs := NewFilters()
+	fs.DefaultAction = ActionDeny
+	gf := NewGuarded(fs)
+	gf.StrictUnresolved = true
+
+	onion := multiaddr.StringCast("/onion3/vww6ybal4bd7szmgncyruucpgfkqahzddi37ktceo3ah7ngmcopnpyyd:1234")
+	garlic := multiaddr.StringCast("/garlic32/566niximlxdzpanmn4qouucvua3k7neniwss47li5r6ugoertzuq")
+	ip := multiaddr.StringCast("/ip4/1.2.3.4/tcp/1")
+
+	for _, tc := range []struct {
+		onion, i2p bool
+	}{
+		{false, false},
+		{true, false},
+		{false, true},
+		{true, true},
+	} {
+		gf.AllowOnion, gf.AllowI2P = tc.onion, tc.i2p
+		if got := gf.AddrBlocked(onion); got != !tc.onion {
+			t.Errorf("AllowOnion=%v: AddrBlocked(%s) = %v", tc.onion, onion, got)
+		}
+		if got := gf.AddrBlocked(garlic); got != !tc.i2p {
+			t.Errorf("AllowI2P=%v: AddrBlocked(%s) = %v", tc.i2p, garlic, got)
+		}
+		if !gf.AddrBlocked(ip) {
+			t.Errorf("%s not blocked by the default policy", ip)
+		}
+	}
+}
@@ -0,0 +1,114 @@
+package filter
+
+import (
+	"fmt"
+	"strings"
+	"sync"
+)
+
+// HostFilters is a collection of hostname deny rules. Unlike Filters, which
+// match on IP addresses, HostFilters match on DNS names such as those found
+// in /dns4 and /dns6 multiaddrs or in a TLS server name. The two are
+// evaluated independently of each other.
+type HostFilters struct {
+	mu    sync.RWMutex
+	exact map[string]struct{}
+	// wildcards holds the ".example.com" suffix of every "*.example.com"
+	// pattern.
+	wildcards []string
+}
+
+// NewHostFilters constructs and returns a new, empty set of hostname filters.
+func NewHostFilters() *HostFilters {
+	return &HostFilters{
+		exact: make(map[string]struct{}),
+	}
+}
+
+// AddDenyHost adds a deny rule for the given hostname pattern. A pattern is
+// either an exact hostname ("example.com"), or a wildcard of the form
+// "*.example.com", which matches every subdomain of example.com (but not
+// example.com itself).
+//
+// Patterns are matched case-insensitively, ignoring any trailing dot. An
+// error is returned, and no rule added, if the pattern is empty, has an empty
+// label, or uses "*" anywhere but as the whole first label.
+func (hf *HostFilters) AddDenyHost(pattern string) error {
+	if err := validateHostPattern(pattern); err != nil {
+		return err
+	}
+	pattern = normalizeHost(pattern)
+
+	hf.mu.Lock()
+	defer hf.mu.Unlock()
+
+	if strings.HasPrefix(pattern, "*.") {
+		suffix := pattern[1:]
+		for _, w := range hf.wildcards {
+			if w == suffix {
+				return nil
+			}
+		}
+		hf.wildcards = append(hf.wildcards, suffix)
+		return nil
+	}
+	hf.exact[pattern] = struct{}{}
+	return nil
+}
+
+// validateHostPattern checks that pattern is a hostname, optionally prefixed
+// with a "*." wildcard, made of non-empty labels of letters, digits, hyphens
+// and underscores.
+func validateHostPattern(pattern string) error {
+	name := strings.TrimPrefix(normalizeHost(pattern), "*.")
+	if name == "" {
+		return fmt.Errorf("invalid host pattern %q: empty name", pattern)
+	}
+	for _, label := range strings.Split(name, ".") {
+		if label == "" || len(label) > 63 {
+			return fmt.Errorf("invalid host pattern %q: bad label length", pattern)
+		}
+		for _, c := range label {
+			if !(c >= 'a' && c <= 'z' || c >= '0' && c <= '9' || c == '-' || c == '_') {
+				return fmt.Errorf("invalid host pattern %q: invalid character %q", pattern, c)
+			}
+		}
+	}
+	return nil
+}
+
+// HostBlocked returns true if the given hostname matches any deny rule.
+func (hf *HostFilters) HostBlocked(host string) bool {
+	host = normalizeHost(host)
+
+	hf.mu.RLock()
+	defer hf.mu.RUnlock()
+
+	if _, ok := hf.exact[host]; ok {
+		return true
+	}
+	for _, suffix := range hf.wildcards {
+		if matchWildcardSuffix(host, suffix) {
+			return true
+		}
+	}
+	return false
+}
+
+// matchHostPattern reports whether host matches the exact or wildcard
+// pattern, using the same rules as HostFilters.
+func matchHostPattern(pattern, host string) bool {
+	pattern, host = normalizeHost(pattern), normalizeHost(host)
+	if strings.HasPrefix(pattern, "*.") {
+		return matchWildcardSuffix(host, pattern[1:])
+	}
+	return host == pattern
+}
+
+func matchWildcardSuffix(host, suffix string) bool {
+	return len(host) > len(suffix) && strings.HasSuffix(host, suffix)
+}
+
+func normalizeHost(host string) string {
+	return strings.ToLower(strings.TrimSuffix(host, "."))
+}
@@ -0,0 +1,57 @@
+package filter
+
+import "testing"
+
+func TestHostBlocked(t *testing.T) {
+	hf := NewHostFilters()
+	for _, pattern := range []string{"Example.COM.", "*.corp.example"} {
+		if err := hf.AddDenyHost(pattern); err != nil {
+			t.Fatal(err)
+		}
+	}
+
+	for host, blocked := range map[string]bool{
+		"example.com":        true,
+		"EXAMPLE.com":        true,
+		"example.com.":       true,
+		"www.example.com":    false,
+		"example.org":        false,
+		"a.corp.example":     true,
+		"A.B.Corp.Example.":  true,
+		"corp.example":       false,
+		"corp.example.":      false,
+		"xcorp.example":      false,
+		"":                   false,
+		".":                  false,
+		"other.example.com.": false,
+	} {
+		if got := hf.HostBlocked(host); got != blocked {
+			t.Errorf("HostBlocked(%q) = %v, want %v", host, got, blocked)
+		}
+	}
+}
+
+func TestAddDenyHostInvalid(t *testing.T) {
+	hf := NewHostFilters()
+	for _, pattern := range []string{
+		"",
+		".",
+		"*",
+		"*.",
+		"**.example.com",
+		"a.*.example.com",
+		"example..com",
+		".example.com",
+		"exa mple.com",
+	} {
+		if err := hf.AddDenyHost(pattern); err == nil {
+			t.Errorf("AddDenyHost(%q) should fail", pattern)
+		}
+	}
+
+	for _, host := range []string{"", ".", "*", "example.com"} {
+		if hf.HostBlocked(host) {
+			t.Errorf("HostBlocked(%q) after only invalid patterns", host)
+		}
+	}
+}
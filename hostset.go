@@ -0,0 +1,63 @@
+package filter
+
+import (
+	"encoding/binary"
+	"net"
+	"sort"
+
+	"github.com/multiformats/go-multiaddr"
+)
+
+// IPv4HostSet is an immutable set of single IPv4 hosts, stored as a sorted
+// array of uint32s. For deny lists made of millions of /32 entries it uses a
+// small fraction of the memory of the equivalent Filters rules, and answers
+// queries in O(log n) rather than by scanning every rule.
+//
+// An IPv4HostSet is independent of any Filters set; check both when using
+// one for a host-heavy deny list alongside CIDR rules.
+type IPv4HostSet struct {
+	hosts []uint32
+}
+
+// NewIPv4HostSet builds a set from the given IPs. IPv6 addresses are ignored,
+// and duplicates are only stored once.
+func NewIPv4HostSet(ips []net.IP) *IPv4HostSet {
+	hosts := make([]uint32, 0, len(ips))
+	for _, ip := range ips {
+		if ip4 := ip.To4(); ip4 != nil {
+			hosts = append(hosts, binary.BigEndian.Uint32(ip4))
+		}
+	}
+	sort.Slice(hosts, func(i, j int) bool { return hosts[i] < hosts[j] })
+
+	uniq := hosts[:0]
+	for i, h := range hosts {
+		if i == 0 || h != hosts[i-1] {
+			uniq = append(uniq, h)
+		}
+	}
+	return &IPv4HostSet{hosts: uniq}
+}
+
+// Len returns the number of hosts in the set.
+func (s *IPv4HostSet) Len() int {
+	return len(s.hosts)
+}
+
+// Contains returns true if the IP is in the set.
+func (s *IPv4HostSet) Contains(ip net.IP) bool {
+	ip4 := ip.To4()
+	if ip4 == nil {
+		return false
+	}
+	h := binary.BigEndian.Uint32(ip4)
+	i := sort.Search(len(s.hosts), func(i int) bool { return s.hosts[i] >= h })
+	return i < len(s.hosts) && s.hosts[i] == h
+}
+
+// AddrBlocked returns true if the address dials an IP in the set, extracting
+// the IP as Filters.AddrBlocked does.
+func (s *IPv4HostSet) AddrBlocked(a multiaddr.Multiaddr) bool {
+	ip, found := addrIP(a)
+	return found && s.Contains(ip)
+}
@@ -0,0 +1,126 @@
+package filter
+
+import (
+	"encoding/binary"
+	"math/rand"
+	"net"
+	"runtime"
+	"testing"
+
+	"github.com/multiformats/go-multiaddr"
+)
+
+func TestIPv4HostSetContains(t *testing.T) {
+	s := NewIPv4HostSet([]net.IP{
+		net.ParseIP("1.2.3.4"),
+		net.IPv4(1, 2, 3, 4).To4(), // duplicate, in 4-byte form
+		net.ParseIP("::ffff:9.9.9.9"),
+		net.ParseIP("2001:db8::1"),
+	})
+
+	if s.Len() != 2 {
+		t.Fatalf("expected 2 hosts, got %d", s.Len())
+	}
+
+	for _, tc := range []struct {
+		ip       string
+		expected bool
+	}{
+		{"1.2.3.4", true},
+		{"::ffff:1.2.3.4", true},
+		{"9.9.9.9", true},
+		{"1.2.3.5", false},
+		{"2001:db8::1", false},
+		{"::1", false},
+	} {
+		if got := s.Contains(net.ParseIP(tc.ip)); got != tc.expected {
+			t.Errorf("Contains(%s) = %t, expected %t", tc.ip, got, tc.expected)
+		}
+	}
+
+	for _, tc := range []struct {
+		addr     string
+		expected bool
+	}{
+		{"/ip4/1.2.3.4/tcp/1", true},
+		{"/ip6/::ffff:9.9.9.9/udp/1", true},
+		{"/ip4/1.2.3.5/tcp/1", false},
+		{"/dns4/example.com/tcp/1", false},
+	} {
+		if got := s.AddrBlocked(multiaddr.StringCast(tc.addr)); got != tc.expected {
+			t.Errorf("AddrBlocked(%s) = %t, expected %t", tc.addr, got, tc.expected)
+		}
+	}
+}
+
+const benchHosts = 1000000
+
+func benchHostIPs() []net.IP {
+	r := rand.New(rand.NewSource(1))
+	ips := make([]net.IP, benchHosts)
+	for i := range ips {
+		ip := make(net.IP, net.IPv4len)
+		binary.BigEndian.PutUint32(ip, r.Uint32())
+		ips[i] = ip
+	}
+	return ips
+}
+
+func heapAlloc() uint64 {
+	runtime.GC()
+	var m runtime.MemStats
+	runtime.ReadMemStats(&m)
+	return m.HeapAlloc
+}
+
+// benchMissIP is not generated by benchHostIPs, so lookups for it have to
+// go all the way.
+var benchMissIP = net.IPv4(0, 0, 0, 0)
+
+func BenchmarkIPv4HostSet(b *testing.B) {
+	ips := benchHostIPs()
+
+	before := heapAlloc()
+	s := NewIPv4HostSet(ips)
+	size := heapAlloc() - before
+	runtime.KeepAlive(ips)
+
+	b.ResetTimer()
+	for i := 0; i < b.N; i++ {
+		s.Contains(benchMissIP)
+	}
+	b.ReportMetric(float64(size)/benchHosts, "B/host")
+}
+
+// BenchmarkIPNetSlice measures the representation Filters uses for the same
+// 1M /32 rules: a slice of entries holding a net.IPNet, scanned with
+// Contains as Filters.AddrBlocked does. Filters itself is not used because
+// AddFilter's duplicate scan makes adding 1M rules impractically slow.
+func BenchmarkIPNetSlice(b *testing.B) {
+	type entry struct {
+		f      net.IPNet
+		action Action
+	}
+	ips := benchHostIPs()
+
+	before := heapAlloc()
+	entries := make([]*entry, 0, len(ips))
+	for _, ip := range ips {
+		// Give each entry its own IP, as rules parsed from a list would have.
+		ip = append(net.IP(nil), ip...)
+		ipnet, _ := hostNet(ip)
+		entries = append(entries, &entry{ipnet, ActionDeny})
+	}
+	size := heapAlloc() - before
+	runtime.KeepAlive(ips)
+
+	b.ResetTimer()
+	for i := 0; i < b.N; i++ {
+		for _, e := range entries {
+			if e.f.Contains(benchMissIP) {
+				break
+			}
+		}
+	}
+	b.ReportMetric(float64(size)/benchHosts, "B/host")
+}
@@ -0,0 +1,24 @@
+package filter
+
+// Invert flips the default policy and the action of every accept and deny
+// rule, turning an allowlist into a blocklist and vice versa. Rule order is
+// preserved. Rules are flipped one at a time, so a concurrent query may see
+// accept rules already turned into deny rules while deny rules are not yet
+// turned into accept rules.
+func Invert(fs *Filters) {
+	accept := fs.FiltersForAction(ActionAccept)
+	deny := fs.FiltersForAction(ActionDeny)
+
+	for _, ipnet := range accept {
+		fs.AddFilter(ipnet, ActionDeny)
+	}
+	for _, ipnet := range deny {
+		fs.AddFilter(ipnet, ActionAccept)
+	}
+
+	if fs.DefaultAction == ActionDeny {
+		fs.DefaultAction = ActionAccept
+	} else {
+		fs.DefaultAction = ActionDeny
+	}
+}
@@ -0,0 +1,20 @@
+package filter
+
+import (
+	"net"
+	"unsafe"
+)
+
+// EstimatedMemoryBytes returns a rough estimate of the memory held by the
+// Filters set: the set itself, plus for each rule its entry and the backing
+// arrays of its IP and mask. It ignores allocator overhead and slice growth
+// headroom, but grows linearly with the number and size of rules.
+func EstimatedMemoryBytes(fs *Filters) int {
+	const entrySize = int(unsafe.Sizeof(uintptr(0)) + unsafe.Sizeof(net.IPNet{}) + unsafe.Sizeof(Action(0)))
+
+	total := int(unsafe.Sizeof(*fs))
+	for _, r := range Rules(fs) {
+		total += entrySize + cap(r.Net.IP) + cap(r.Net.Mask)
+	}
+	return total
+}
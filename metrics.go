@@ -0,0 +1,39 @@
+package filter
+
+import (
+	"fmt"
+	"io"
+)
+
+// WritePrometheus writes metrics describing the Filters set to w in the
+// Prometheus text exposition format: the number of rules per action and
+// whether the default policy denies. All metric names are prefixed with
+// "maddrfilter_".
+//
+// Filters does not count queries, so no query or match metrics are emitted.
+func WritePrometheus(fs *Filters, w io.Writer) error {
+	if _, err := fmt.Fprint(w,
+		"# HELP maddrfilter_rules Number of filter rules by action.\n",
+		"# TYPE maddrfilter_rules gauge\n",
+	); err != nil {
+		return err
+	}
+	for _, action := range actions {
+		n := len(fs.FiltersForAction(action))
+		if _, err := fmt.Fprintf(w, "maddrfilter_rules{action=%q} %d\n", actionName(action), n); err != nil {
+			return err
+		}
+	}
+
+	deny := 0
+	if fs.DefaultAction == ActionDeny {
+		deny = 1
+	}
+	_, err := fmt.Fprintf(w,
+		"# HELP maddrfilter_default_deny Whether addresses matching no rule are denied.\n"+
+			"# TYPE maddrfilter_default_deny gauge\n"+
+			"maddrfilter_default_deny %d\n",
+		deny,
+	)
+	return err
+}
@@ -0,0 +1,179 @@
+package filter
+
+import (
+	"math/big"
+	"net"
+)
+
+// Minimize returns a new Filters set that reaches the same verdict as fs for
+// every address, using as few rules as possible. The rules of the result are
+// ordered from least to most specific, so the most specific matching rule
+// always decides.
+//
+// The rule set is computed with the ORTC (Optimal Routing Table Constructor)
+// algorithm, separately for each address family. Accept and ActionNone rules
+// are both rendered as accept rules, since neither denies.
+func Minimize(fs *Filters) *Filters {
+	out := NewFilters()
+	out.DefaultAction = fs.DefaultAction
+	for _, r := range minimizedRules(fs) {
+		out.AddFilter(r.Net, r.Action)
+	}
+	return out
+}
+
+// minimizedRules returns the rules of Minimize(fs), from least to most
+// specific.
+func minimizedRules(fs *Filters) []Rule {
+	var out []Rule
+	inherited := verdictBit(fs.DefaultAction == ActionDeny)
+	rules := allRules(fs)
+	for _, size := range families {
+		out = append(out, minimalRules(fs, rules, size, inherited)...)
+	}
+	return out
+}
+
+const (
+	verdictAccept uint8 = 1 << iota
+	verdictDeny
+)
+
+func verdictBit(deny bool) uint8 {
+	if deny {
+		return verdictDeny
+	}
+	return verdictAccept
+}
+
+// trieNode is a node of a binary prefix trie over one address family. Every
+// node has either zero or two children.
+type trieNode struct {
+	child [2]*trieNode
+	// set holds the verdicts this prefix may be labelled with: the
+	// verdict of the region for leaves, and the ORTC merge of the
+	// children's sets for inner nodes.
+	set uint8
+}
+
+// minimalRules returns the smallest list of rules of the given address
+// family that, ordered from least to most specific and on top of the
+// inherited verdict, reproduces the verdicts of fs.
+func minimalRules(fs *Filters, rules []net.IPNet, size int, inherited uint8) []Rule {
+	root := buildTrie(fs, rules, size)
+	root.merge()
+
+	var out []Rule
+	root.emit(new(big.Int), 0, size, inherited, &out)
+	return out
+}
+
+// buildTrie splits the address space into regions over which fs reaches a
+// single verdict, and inserts them into a trie as aligned prefixes.
+func buildTrie(fs *Filters, rules []net.IPNet, size int) *trieNode {
+	root := &trieNode{}
+	one := big.NewInt(1)
+	points, mapped := familyRegions(rules, size)
+	for i := 0; i < len(points)-1; i++ {
+		lo, hi := points[i], points[i+1]
+
+		// IPv4-mapped regions are unreachable over IPv6, so any verdict
+		// will do.
+		set := verdictAccept | verdictDeny
+		if !mapped(lo) {
+			set = verdictBit(IPBlocked(fs, intToIP(lo, size)))
+		}
+
+		last := new(big.Int).Sub(hi, one)
+		for _, ipnet := range rangeToCIDRs(lo, last, size) {
+			ones, _ := ipnet.Mask.Size()
+			root.insert(ipToInt(ipnet.IP), ones, 8*size, set)
+		}
+	}
+	return root
+}
+
+func (n *trieNode) insert(prefix *big.Int, ones, bits int, set uint8) {
+	for depth := 0; depth < ones; depth++ {
+		bit := prefix.Bit(bits - 1 - depth)
+		if n.child[bit] == nil {
+			n.child[bit] = &trieNode{}
+		}
+		n = n.child[bit]
+	}
+	n.set = set
+}
+
+// merge computes the ORTC verdict sets of inner nodes, bottom-up.
+func (n *trieNode) merge() {
+	if n.child[0] == nil {
+		return
+	}
+	n.child[0].merge()
+	n.child[1].merge()
+
+	a, b := n.child[0].set, n.child[1].set
+	if a&b != 0 {
+		n.set = a & b
+	} else {
+		n.set = a | b
+	}
+}
+
+// emit walks the trie top-down, appending a rule whenever the verdict
+// inherited from the enclosing rules is not acceptable for a prefix.
+func (n *trieNode) emit(prefix *big.Int, depth, size int, inherited uint8, out *[]Rule) {
+	label := inherited
+	if n.set&inherited == 0 {
+		label = verdictDeny
+		if n.set&verdictDeny == 0 {
+			label = verdictAccept
+		}
+
+		action := ActionAccept
+		if label == verdictDeny {
+			action = ActionDeny
+		}
+		*out = append(*out, Rule{
+			Net: net.IPNet{
+				IP:   intToIP(prefix, size),
+				Mask: net.CIDRMask(depth, 8*size),
+			},
+			Action: action,
+		})
+	}
+
+	if n.child[0] == nil {
+		return
+	}
+	bits := 8 * size
+	n.child[0].emit(prefix, depth+1, size, label, out)
+	upper := new(big.Int).SetBit(new(big.Int).Set(prefix), bits-1-depth, 1)
+	n.child[1].emit(upper, depth+1, size, label, out)
+}
+
+// WithExplicitDefault returns a new Filters set equivalent to fs in which the
+// default policy is also spelled out as explicit 0.0.0.0/0 and ::/0 rules,
+// placed first so that the remaining rules override them. The remaining
+// rules are those of Minimize(fs), since Filters does not expose the
+// relative order of rules with different actions.
+//
+// The result keeps the DefaultAction of fs, which still applies to
+// addresses without an IP.
+func WithExplicitDefault(fs *Filters) *Filters {
+	action := ActionAccept
+	if fs.DefaultAction == ActionDeny {
+		action = ActionDeny
+	}
+
+	out := NewFilters()
+	out.DefaultAction = fs.DefaultAction
+	for _, size := range families {
+		bits := 8 * size
+		out.AddFilter(net.IPNet{IP: make(net.IP, size), Mask: net.CIDRMask(0, bits)}, action)
+	}
+	for _, r := range minimizedRules(fs) {
+		out.AddFilter(r.Net, r.Action)
+	}
+	return out
+}
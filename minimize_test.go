@@ -0,0 +1,109 @@
+package filter
+
+import (
+	"math/big"
+	"math/rand"
+	"net"
+	"testing"
+)
+
+// randomFilters builds a set of n rules nested within a handful of base
+// networks, mixing address families, IPv4-mapped IPv6 rules and all three
+// actions, so that rules overlap in every way.
+func randomFilters(r *rand.Rand, n int) *Filters {
+	bases := []string{
+		"0.0.0.0/0",
+		"10.0.0.0/8",
+		"192.168.0.0/16",
+		"::/0",
+		"::/64",
+		"2001:db8::/32",
+		"::ffff:10.0.0.0/104",
+		"::ffff:0:0/96",
+	}
+	actions := []Action{ActionNone, ActionAccept, ActionDeny}
+
+	fs := NewFilters()
+	if r.Intn(2) == 0 {
+		fs.DefaultAction = ActionDeny
+	}
+	for i := 0; i < n; i++ {
+		_, base, err := net.ParseCIDR(bases[r.Intn(len(bases))])
+		if err != nil {
+			panic(err)
+		}
+		ones, bits := base.Mask.Size()
+		ones += r.Intn(bits - ones + 1)
+
+		ip := append(net.IP(nil), base.IP...)
+		mask := net.CIDRMask(ones, bits)
+		for j := range ip {
+			ip[j] |= byte(r.Intn(256)) &^ base.Mask[j]
+		}
+
+		fs.AddFilter(net.IPNet{IP: ip.Mask(mask), Mask: mask}, actions[r.Intn(len(actions))])
+	}
+	return fs
+}
+
+// probeIPs returns IPs around every rule boundary of fs, in both their
+// native and IPv4-mapped forms, plus random IPs of both families.
+func probeIPs(r *rand.Rand, fs *Filters) []net.IP {
+	var ips []net.IP
+	for _, size := range families {
+		for _, p := range boundaries(allRules(fs), size) {
+			for _, d := range []int64{-1, 0, 1} {
+				x := new(big.Int).Add(p, big.NewInt(d))
+				if x.Sign() < 0 || x.BitLen() > 8*size {
+					continue
+				}
+				ip := intToIP(x, size)
+				ips = append(ips, ip, ip.To16())
+			}
+		}
+	}
+	for i := 0; i < 100; i++ {
+		ip4 := make(net.IP, net.IPv4len)
+		ip6 := make(net.IP, net.IPv6len)
+		r.Read(ip4)
+		r.Read(ip6)
+		ips = append(ips, ip4, ip6)
+	}
+	return ips
+}
+
+func TestMinimize(t *testing.T) {
+	r := rand.New(rand.NewSource(1))
+	for i := 0; i < 300; i++ {
+		fs := randomFilters(r, 1+r.Intn(20))
+		min := Minimize(fs)
+
+		if !Equivalent(fs, min) {
+			t.Fatalf("minimized set is not equivalent:\n%v\n%v", Rules(fs), Rules(min))
+		}
+		if min.DefaultAction != fs.DefaultAction {
+			t.Fatalf("default changed from %v to %v", fs.DefaultAction, min.DefaultAction)
+		}
+		if len(Rules(min)) > len(Rules(fs)) {
+			t.Fatalf("minimized set grew from %d to %d rules:\n%v\n%v", len(Rules(fs)), len(Rules(min)), Rules(fs), Rules(min))
+		}
+		for _, ip := range probeIPs(r, fs) {
+			if IPBlocked(fs, ip) != IPBlocked(min, ip) {
+				t.Fatalf("verdict for %s differs:\n%v\n%v", ip, Rules(fs), Rules(min))
+			}
+		}
+	}
+}
+
+func TestMinimizeMergesSiblings(t *testing.T) {
+	fs := NewFilters()
+	for _, cidr := range []string{"10.0.0.0/9", "10.128.0.0/9"} {
+		_, ipnet, _ := net.ParseCIDR(cidr)
+		fs.AddFilter(*ipnet, ActionDeny)
+	}
+
+	got := DenyCIDRStrings(Minimize(fs))
+	if len(got) != 1 || got[0] != "10.0.0.0/8" {
+		t.Fatalf("expected [10.0.0.0/8], got %v", got)
+	}
+}
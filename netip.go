@@ -0,0 +1,78 @@
+//go:build go1.18
+// +build go1.18
+
+package filter
+
+import (
+	"fmt"
+	"net"
+	"net/netip"
+)
+
+// AddDialPrefix adds a deny rule for the given netip.Prefix. It returns an
+// error, and adds nothing, if the prefix is invalid, such as the zero
+// Prefix.
+//
+// The prefix is converted to a net.IPNet, which is what Filters stores.
+func AddDialPrefix(fs *Filters, p netip.Prefix) error {
+	if !p.IsValid() {
+		return fmt.Errorf("invalid prefix %s", p)
+	}
+	fs.AddFilter(prefixToIPNet(p), ActionDeny)
+	return nil
+}
+
+// PrefixBlocked returns true if the Filters set denies the given netip.Addr.
+func PrefixBlocked(fs *Filters, addr netip.Addr) bool {
+	return IPBlocked(fs, net.IP(addr.AsSlice()))
+}
+
+func prefixToIPNet(p netip.Prefix) net.IPNet {
+	p = p.Masked()
+	return net.IPNet{
+		IP:   net.IP(p.Addr().AsSlice()),
+		Mask: net.CIDRMask(p.Bits(), p.Addr().BitLen()),
+	}
+}
+
+// DenyPrefixes returns the networks of all deny rules as netip.Prefix
+// values, in rule order as for DenyCIDRStrings. Rules that cannot be represented as
+// a prefix are skipped.
+func DenyPrefixes(fs *Filters) []netip.Prefix {
+	return prefixesForAction(fs, ActionDeny)
+}
+
+// AllowPrefixes returns the networks of all accept rules as netip.Prefix
+// values, in rule order as for AllowCIDRStrings. Rules that cannot be represented as
+// a prefix are skipped.
+func AllowPrefixes(fs *Filters) []netip.Prefix {
+	return prefixesForAction(fs, ActionAccept)
+}
+
+func prefixesForAction(fs *Filters, action Action) []netip.Prefix {
+	var prefixes []netip.Prefix
+	for _, ipnet := range fs.FiltersForAction(action) {
+		if p, ok := ipNetToPrefix(ipnet); ok {
+			prefixes = append(prefixes, p)
+		}
+	}
+	return prefixes
+}
+
+func ipNetToPrefix(ipnet net.IPNet) (netip.Prefix, bool) {
+	ones, bits := ipnet.Mask.Size()
+	if bits == 0 {
+		return netip.Prefix{}, false
+	}
+	addr, ok := netip.AddrFromSlice(ipnet.IP)
+	if !ok {
+		return netip.Prefix{}, false
+	}
+	if bits == 8*net.IPv4len {
+		addr = addr.Unmap()
+	}
+	if addr.BitLen() != bits {
+		return netip.Prefix{}, false
+	}
+	return netip.PrefixFrom(addr, ones).Masked(), true
+}
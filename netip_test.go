@@ -0,0 +1,31 @@
+//go:build go1.18
+// +build go1.18
+
+package filter
+
+import (
+	"net/netip"
+	"testing"
+)
+
+func TestAddDialPrefix(t *testing.T) {
+	fs := NewFilters()
+	if err := AddDialPrefix(fs, netip.MustParsePrefix("10.1.2.3/16")); err != nil {
+		t.Fatal(err)
+	}
+	if got := DenyPrefixes(fs); len(got) != 1 || got[0] != netip.MustParsePrefix("10.1.0.0/16") {
+		t.Fatalf("expected [10.1.0.0/16], got %v", got)
+	}
+	if !PrefixBlocked(fs, netip.MustParseAddr("10.1.255.255")) {
+		t.Fatal("10.1.255.255 should be blocked")
+	}
+
+	for _, p := range []netip.Prefix{{}, netip.PrefixFrom(netip.MustParseAddr("10.0.0.0"), 33)} {
+		if err := AddDialPrefix(fs, p); err == nil {
+			t.Errorf("AddDialPrefix(%s) should fail", p)
+		}
+	}
+	if n := len(Rules(fs)); n != 1 {
+		t.Fatalf("invalid prefixes added rules: %v", Rules(fs))
+	}
+}
@@ -0,0 +1,91 @@
+package filter
+
+import (
+	"bufio"
+	"fmt"
+	"io"
+	"net"
+	"strings"
+)
+
+// ParsePrefixedCIDRs builds a Filters set from a list of CIDRs, one per
+// line, each prefixed with "+" to accept or "-" to deny:
+//
+//	+10.0.0.0/8
+//	-192.168.0.0/16
+//
+// Rules are added in the order they appear. Blank lines and lines starting
+// with "#" are ignored.
+func ParsePrefixedCIDRs(r io.Reader) (*Filters, error) {
+	fs := NewFilters()
+
+	s := bufio.NewScanner(r)
+	for lineno := 1; s.Scan(); lineno++ {
+		line := strings.TrimSpace(s.Text())
+		if line == "" || strings.HasPrefix(line, "#") {
+			continue
+		}
+
+		var action Action
+		switch line[0] {
+		case '+':
+			action = ActionAccept
+		case '-':
+			action = ActionDeny
+		default:
+			return nil, fmt.Errorf("line %d: missing +/- prefix in %q", lineno, line)
+		}
+
+		_, ipnet, err := net.ParseCIDR(strings.TrimSpace(line[1:]))
+		if err != nil {
+			return nil, fmt.Errorf("line %d: invalid CIDR %q", lineno, line[1:])
+		}
+		fs.AddFilter(*ipnet, action)
+	}
+	if err := s.Err(); err != nil {
+		return nil, err
+	}
+	return fs, nil
+}
+
+// ParseMixedList parses a list of networks, one per line, where each line is
+// either a CIDR ("10.0.0.0/8"), an inclusive IP range ("10.0.0.0-10.0.0.255")
+// or a single IP. Ranges are decomposed as by ParseIPRange, and single IPs
+// become /32 or /128 networks. Blank lines and lines starting with "#" are
+// ignored.
+func ParseMixedList(r io.Reader) ([]*net.IPNet, error) {
+	var nets []*net.IPNet
+
+	s := bufio.NewScanner(r)
+	for lineno := 1; s.Scan(); lineno++ {
+		line := strings.TrimSpace(s.Text())
+		if line == "" || strings.HasPrefix(line, "#") {
+			continue
+		}
+
+		switch {
+		case strings.Contains(line, "-"):
+			ranges, err := ParseIPRange(line)
+			if err != nil {
+				return nil, fmt.Errorf("line %d: %s", lineno, err)
+			}
+			nets = append(nets, ranges...)
+		case strings.Contains(line, "/"):
+			_, ipnet, err := net.ParseCIDR(line)
+			if err != nil {
+				return nil, fmt.Errorf("line %d: invalid CIDR %q", lineno, line)
+			}
+			nets = append(nets, ipnet)
+		default:
+			ipnet, ok := hostNet(net.ParseIP(line))
+			if !ok {
+				return nil, fmt.Errorf("line %d: invalid IP %q", lineno, line)
+			}
+			nets = append(nets, &ipnet)
+		}
+	}
+	if err := s.Err(); err != nil {
+		return nil, err
+	}
+	return nets, nil
+}
@@ -0,0 +1,46 @@
+package filter
+
+import (
+	"context"
+	"net"
+
+	"github.com/multiformats/go-multiaddr"
+)
+
+// AddrBlockedPTR performs a reverse DNS lookup of the address' IP and returns
+// true if any of the returned names matches one of the given hostname
+// patterns. Patterns use the same syntax as HostFilters.AddDenyHost.
+//
+// This is independent of any Filters set and requires network access; use
+// it as an opt-in check alongside Filters.AddrBlocked. If r is nil,
+// net.DefaultResolver is used. Addresses without an IP, and IPs without PTR
+// records, are not blocked.
+func AddrBlockedPTR(ctx context.Context, r *net.Resolver, a multiaddr.Multiaddr, patterns []string) (bool, error) {
+	if len(patterns) == 0 {
+		return false, nil
+	}
+	ip, found := addrIP(a)
+	if !found {
+		return false, nil
+	}
+	if r == nil {
+		r = net.DefaultResolver
+	}
+
+	names, err := r.LookupAddr(ctx, ip.String())
+	if err != nil {
+		if dnsErr, ok := err.(*net.DNSError); ok && dnsErr.IsNotFound {
+			return false, nil
+		}
+		return false, err
+	}
+
+	for _, name := range names {
+		for _, pattern := range patterns {
+			if matchHostPattern(pattern, name) {
+				return true, nil
+			}
+		}
+	}
+	return false, nil
+}
@@ -0,0 +1,71 @@
+package filter
+
+import "net"
+
+// MostSpecific returns the narrowest rule containing the IP along with
+// whether that rule denies it, regardless of which rule actually decides the
+// verdict. The last result is false if no rule contains the IP.
+func MostSpecific(fs *Filters, ip net.IP) (ipnet *net.IPNet, deny bool, ok bool) {
+	best := -1
+	for _, action := range actions {
+		for _, ff := range fs.FiltersForAction(action) {
+			if !ff.Contains(ip) {
+				continue
+			}
+			if ones, _ := ff.Mask.Size(); ones > best {
+				ff := ff
+				best, ipnet, deny = ones, &ff, action == ActionDeny
+			}
+		}
+	}
+	return ipnet, deny, best >= 0
+}
+
+// Overlapping returns the rules whose network intersects candidate, that
+// is, rules that contain candidate or are contained by it. Since IP networks
+// are either nested or disjoint, this covers every kind of overlap.
+func Overlapping(fs *Filters, candidate *net.IPNet) []Rule {
+	var overlapping []Rule
+	for _, r := range Rules(fs) {
+		if Covers(&r.Net, candidate) || Covers(candidate, &r.Net) {
+			overlapping = append(overlapping, r)
+		}
+	}
+	return overlapping
+}
+
+// WouldConflict returns the existing rules that would interact with a new
+// rule for f, denying if reject is set: the overlapping rules with the
+// opposite verdict. Since the last matching rule wins, the new rule overrides
+// those rules where they overlap; if f already has a rule, overlapping rules
+// added after it keep overriding it.
+//
+// A rule for exactly f is not reported, as adding f updates it in place.
+func WouldConflict(fs *Filters, f *net.IPNet, reject bool) []Rule {
+	key := f.String()
+	var conflicts []Rule
+	for _, r := range Overlapping(fs, f) {
+		if (r.Action == ActionDeny) != reject && r.Net.String() != key {
+			conflicts = append(conflicts, r)
+		}
+	}
+	return conflicts
+}
+
+// Mentions returns true if any rule, accept or deny, contains the IP,
+// regardless of the verdict the Filters set reaches for it.
+func Mentions(fs *Filters, ip net.IP) bool {
+	return matchesAny(fs, ip)
+}
+
+// MatchCount returns the number of rules, accept or deny, whose network
+// contains the IP. A high count often points at redundant rules.
+func MatchCount(fs *Filters, ip net.IP) int {
+	count := 0
+	for _, r := range Rules(fs) {
+		if r.Net.Contains(ip) {
+			count++
+		}
+	}
+	return count
+}
@@ -0,0 +1,109 @@
+package filter
+
+import (
+	"net"
+	"sync"
+	"time"
+
+	"github.com/multiformats/go-multiaddr"
+	"golang.org/x/time/rate"
+)
+
+// limiterIdleTimeout is how long a per-IP limiter may go unused before it is
+// garbage-collected.
+const limiterIdleTimeout = 10 * time.Minute
+
+// limiterIPv6Prefix is the prefix length IPv6 limiters are keyed by. A single
+// host is usually handed a whole /64, and could otherwise get a fresh
+// limiter by dialing from another address within it.
+const limiterIPv6Prefix = 64
+
+type limiterEntry struct {
+	limiter  *rate.Limiter
+	lastSeen time.Time
+}
+
+// RateLimitedFilters wraps a Filters set with per-IP rate limiting. Addresses
+// are blocked when either the underlying filters deny them, or the address'
+// IP has dialed more frequently than the configured rate allows. IPv6
+// addresses share a limiter with the rest of their /64.
+type RateLimitedFilters struct {
+	*Filters
+
+	limit rate.Limit
+	burst int
+
+	mu        sync.Mutex
+	limiters  map[string]*limiterEntry
+	lastSweep time.Time
+}
+
+// NewRateLimited wraps the given Filters, allowing each IP up to limit dials
+// per second with bursts of at most burst dials.
+func NewRateLimited(fs *Filters, limit rate.Limit, burst int) *RateLimitedFilters {
+	return &RateLimitedFilters{
+		Filters:   fs,
+		limit:     limit,
+		burst:     burst,
+		limiters:  make(map[string]*limiterEntry),
+		lastSweep: time.Now(),
+	}
+}
+
+// AddrBlocked returns true if the underlying Filters deny the address, or if
+// the rate limiter for the address' IP has no tokens left. Addresses without
+// an IP are never rate limited.
+//
+// Only addresses accepted by the underlying Filters consume a token.
+func (rf *RateLimitedFilters) AddrBlocked(a multiaddr.Multiaddr) bool {
+	if rf.Filters.AddrBlocked(a) {
+		return true
+	}
+
+	ip, found := addrIP(a)
+	if !found {
+		return false
+	}
+
+	return !rf.allow(limiterKey(ip))
+}
+
+// limiterKey returns the key of the limiter for ip: the IPv4 address itself,
+// or the IPv6 /64 containing it.
+func limiterKey(ip net.IP) string {
+	if ip4 := ip.To4(); ip4 != nil {
+		return ip4.String()
+	}
+	mask := net.CIDRMask(limiterIPv6Prefix, 8*net.IPv6len)
+	return (&net.IPNet{IP: ip.Mask(mask), Mask: mask}).String()
+}
+
+func (rf *RateLimitedFilters) allow(key string) bool {
+	rf.mu.Lock()
+	defer rf.mu.Unlock()
+
+	now := time.Now()
+	if now.Sub(rf.lastSweep) >= limiterIdleTimeout {
+		rf.sweep(now)
+	}
+
+	le, ok := rf.limiters[key]
+	if !ok {
+		le = &limiterEntry{limiter: rate.NewLimiter(rf.limit, rf.burst)}
+		rf.limiters[key] = le
+	}
+	le.lastSeen = now
+
+	return le.limiter.AllowN(now, 1)
+}
+
+// sweep drops limiters that have been idle for longer than
+// limiterIdleTimeout. Must be called with rf.mu held.
+func (rf *RateLimitedFilters) sweep(now time.Time) {
+	for key, le := range rf.limiters {
+		if now.Sub(le.lastSeen) >= limiterIdleTimeout {
+			delete(rf.limiters, key)
+		}
+	}
+	rf.lastSweep = now
+}
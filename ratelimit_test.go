@@ -0,0 +1,70 @@
+package filter
+
+import (
+	"net"
+	"testing"
+	"time"
+
+	"github.com/multiformats/go-multiaddr"
+	"golang.org/x/time/rate"
+)
+
+func TestRateLimitedBurst(t *testing.T) {
+	rf := NewRateLimited(NewFilters(), rate.Every(time.Hour), 2)
+
+	a := multiaddr.StringCast("/ip4/1.2.3.4/tcp/1")
+	for i := 0; i < 2; i++ {
+		if rf.AddrBlocked(a) {
+			t.Fatalf("dial %d of %s blocked within the burst", i+1, a)
+		}
+	}
+	if !rf.AddrBlocked(a) {
+		t.Fatalf("%s not blocked after exhausting the burst", a)
+	}
+
+	if other := multiaddr.StringCast("/ip4/1.2.3.5/tcp/1"); rf.AddrBlocked(other) {
+		t.Fatalf("%s shares a limiter with %s", other, a)
+	}
+}
+
+func TestRateLimitedIPv6Prefix(t *testing.T) {
+	rf := NewRateLimited(NewFilters(), rate.Every(time.Hour), 2)
+
+	// Rotating addresses within a /64 draws from the same bucket.
+	for _, addr := range []string{"/ip6/2001:db8::1/tcp/1", "/ip6/2001:db8::2/tcp/1"} {
+		if a := multiaddr.StringCast(addr); rf.AddrBlocked(a) {
+			t.Fatalf("%s blocked within the burst", a)
+		}
+	}
+	if a := multiaddr.StringCast("/ip6/2001:db8::3/tcp/1"); !rf.AddrBlocked(a) {
+		t.Fatalf("%s not blocked after its /64 exhausted the burst", a)
+	}
+	if a := multiaddr.StringCast("/ip6/2001:db8:0:1::1/tcp/1"); rf.AddrBlocked(a) {
+		t.Fatalf("%s shares a limiter with another /64", a)
+	}
+	if n := len(rf.limiters); n != 2 {
+		t.Fatalf("expected 2 limiters, got %d", n)
+	}
+}
+
+func TestRateLimitedDeniedDoesNotConsume(t *testing.T) {
+	fs := NewFilters()
+	ipnet, _ := hostNet(net.ParseIP("1.2.3.4"))
+	fs.AddFilter(ipnet, ActionDeny)
+	rf := NewRateLimited(fs, rate.Every(time.Hour), 1)
+
+	a := multiaddr.StringCast("/ip4/1.2.3.4/tcp/1")
+	for i := 0; i < 3; i++ {
+		if !rf.AddrBlocked(a) {
+			t.Fatalf("%s not blocked by its deny rule", a)
+		}
+	}
+
+	fs.RemoveLiteral(ipnet)
+	if rf.AddrBlocked(a) {
+		t.Fatalf("%s blocked: denied dials consumed its token", a)
+	}
+	if !rf.AddrBlocked(a) {
+		t.Fatalf("%s not blocked after exhausting the burst", a)
+	}
+}
@@ -0,0 +1,143 @@
+package filter
+
+import (
+	"math/big"
+	"net"
+	"sort"
+)
+
+// families lists the address sizes, in bytes, of the families a Filters set
+// can match.
+var families = []int{net.IPv4len, net.IPv6len}
+
+// netSpan returns the address family (as an address size in bytes) and the
+// half-open range [lo, hi) of addresses matched by ipnet. IPv4 networks
+// written in their IPv4-mapped IPv6 form are reported as IPv4, mirroring
+// net.IPNet.Contains.
+func netSpan(ipnet net.IPNet) (size int, lo, hi *big.Int, ok bool) {
+	ones, bits := ipnet.Mask.Size()
+	if bits == 0 {
+		return 0, nil, nil, false
+	}
+
+	ip := ipnet.IP.Mask(ipnet.Mask)
+	switch {
+	case bits == 8*net.IPv4len && ip.To4() != nil:
+		ip = ip.To4()
+	case bits == 8*net.IPv6len && ip.To4() != nil:
+		ip, bits, ones = ip.To4(), 8*net.IPv4len, ones-8*(net.IPv6len-net.IPv4len)
+		if ones < 0 {
+			ones = 0
+		}
+	case bits == 8*net.IPv6len && len(ip) == net.IPv6len:
+	default:
+		return 0, nil, nil, false
+	}
+
+	lo = ipToInt(ip)
+	hi = new(big.Int).Lsh(big.NewInt(1), uint(bits-ones))
+	hi.Add(hi, lo)
+	return len(ip), lo, hi, true
+}
+
+// boundaries returns, for the given address family, the sorted, distinct
+// start and end points of the given networks, plus any extra points and the
+// start and end of the address space. Every network either contains or is disjoint from each
+// range between two consecutive boundaries, so a Filters set made of these
+// networks reaches the same verdict for every IP in such a range.
+func boundaries(nets []net.IPNet, size int, extra ...*big.Int) []*big.Int {
+	points := append([]*big.Int{
+		new(big.Int),
+		new(big.Int).Lsh(big.NewInt(1), uint(8*size)),
+	}, extra...)
+	for _, ipnet := range nets {
+		if s, lo, hi, ok := netSpan(ipnet); ok && s == size {
+			points = append(points, lo, hi)
+		}
+	}
+
+	sort.Slice(points, func(i, j int) bool { return points[i].Cmp(points[j]) < 0 })
+	uniq := points[:1]
+	for _, p := range points[1:] {
+		if p.Cmp(uniq[len(uniq)-1]) != 0 {
+			uniq = append(uniq, p)
+		}
+	}
+	return uniq
+}
+
+// mappedIPv4 returns the range of IPv4-mapped IPv6 addresses,
+// ::ffff:0:0/96. Filters treats such addresses as IPv4, so IPv6 rules never
+// apply to them.
+func mappedIPv4() (lo, hi *big.Int) {
+	lo = ipToInt(net.IPv4(0, 0, 0, 0))
+	hi = new(big.Int).Lsh(big.NewInt(1), 8*net.IPv4len)
+	return lo, hi.Add(hi, lo)
+}
+
+// familyRegions returns the boundaries of the given networks for one address
+// family, along with a function reporting whether the region starting at a
+// boundary is IPv4-mapped. Such regions only exist for IPv6, and are matched
+// as IPv4 by Filters, so callers usually skip them.
+func familyRegions(nets []net.IPNet, size int) (points []*big.Int, mapped func(lo *big.Int) bool) {
+	if size != net.IPv6len {
+		return boundaries(nets, size), func(*big.Int) bool { return false }
+	}
+
+	mappedLo, mappedHi := mappedIPv4()
+	points = boundaries(nets, size, mappedLo, mappedHi)
+	return points, func(lo *big.Int) bool {
+		return lo.Cmp(mappedLo) >= 0 && lo.Cmp(mappedHi) < 0
+	}
+}
+
+// allRules returns the networks of every rule in the Filters set, regardless
+// of their action.
+func allRules(fs *Filters) []net.IPNet {
+	var nets []net.IPNet
+	for _, action := range actions {
+		nets = append(nets, fs.FiltersForAction(action)...)
+	}
+	return nets
+}
+
+// walkRegions calls f, in address order, for every region of one address
+// family over which the Filters set reaches a single verdict, skipping
+// IPv4-mapped regions. covered reports whether any rule, whatever its action,
+// contains the region. The rules are read once, and each region costs a
+// single AddrBlocked call.
+func walkRegions(fs *Filters, size int, f func(lo, hi *big.Int, covered, blocked bool)) {
+	rules := allRules(fs)
+	var starts, ends []*big.Int
+	for _, ipnet := range rules {
+		if s, lo, hi, ok := netSpan(ipnet); ok && s == size {
+			starts = append(starts, lo)
+			ends = append(ends, hi)
+		}
+	}
+	sortInts(starts)
+	sortInts(ends)
+
+	// A rule contains the region starting at lo if it starts at or before
+	// lo and ends after it. As regions never straddle a rule boundary,
+	// counting the starts and ends passed so far is enough.
+	points, mapped := familyRegions(rules, size)
+	var started, ended int
+	for i := 0; i < len(points)-1; i++ {
+		lo := points[i]
+		for started < len(starts) && starts[started].Cmp(lo) <= 0 {
+			started++
+		}
+		for ended < len(ends) && ends[ended].Cmp(lo) <= 0 {
+			ended++
+		}
+		if mapped(lo) {
+			continue
+		}
+		f(lo, points[i+1], started > ended, IPBlocked(fs, intToIP(lo, size)))
+	}
+}
+
+func sortInts(xs []*big.Int) {
+	sort.Slice(xs, func(i, j int) bool { return xs[i].Cmp(xs[j]) < 0 })
+}
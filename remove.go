@@ -0,0 +1,45 @@
+package filter
+
+import "net"
+
+// RemoveCovering removes every rule, accept or deny, whose network contains
+// the IP, and returns the number of rules removed.
+//
+// This differs from Filters.RemoveLiteral (and the deprecated Remove), which
+// only remove a rule whose network is exactly the one given: removing
+// 1.2.3.0/25 leaves a 1.2.3.0/24 rule in place, whereas RemoveCovering with
+// any IP in 1.2.3.0/24 removes it.
+func RemoveCovering(fs *Filters, ip net.IP) int {
+	removed := 0
+	for _, action := range actions {
+		for _, ipnet := range fs.FiltersForAction(action) {
+			if ipnet.Contains(ip) && fs.RemoveLiteral(ipnet) {
+				removed++
+			}
+		}
+	}
+	return removed
+}
+
+// ResetToDefaults returns the Filters set to the state NewFilters creates:
+// no rules, and a default policy accepting all addresses. The rules are
+// removed one at a time before the default policy is reset.
+func ResetToDefaults(fs *Filters) {
+	for _, r := range Rules(fs) {
+		fs.RemoveLiteral(r.Net)
+	}
+	fs.DefaultAction = ActionAccept
+}
+
+// RemoveAll removes the rule for exactly each of the given networks, as
+// Filters.RemoveLiteral does, and returns the number of rules removed. Nets
+// that have no rule, or that appear twice, are not counted.
+func RemoveAll(fs *Filters, nets ...*net.IPNet) int {
+	removed := 0
+	for _, ipnet := range nets {
+		if fs.RemoveLiteral(*ipnet) {
+			removed++
+		}
+	}
+	return removed
+}
@@ -0,0 +1,48 @@
+package filter
+
+import (
+	"net"
+	"testing"
+)
+
+func TestRemoveLiteralVersusCovering(t *testing.T) {
+	_, slash24, _ := net.ParseCIDR("1.2.3.0/24")
+	_, slash25, _ := net.ParseCIDR("1.2.3.0/25")
+
+	fs := NewFilters()
+	fs.AddFilter(*slash24, ActionDeny)
+
+	if fs.RemoveLiteral(*slash25) {
+		t.Fatal("RemoveLiteral removed a rule for a network that has none")
+	}
+	if got := fs.FiltersForAction(ActionDeny); len(got) != 1 || got[0].String() != slash24.String() {
+		t.Fatalf("expected the /24 rule to remain, got %v", got)
+	}
+
+	if n := RemoveCovering(fs, net.ParseIP("1.2.3.1")); n != 1 {
+		t.Fatalf("RemoveCovering removed %d rules, want 1", n)
+	}
+	if got := fs.FiltersForAction(ActionDeny); len(got) != 0 {
+		t.Fatalf("expected no rules to remain, got %v", got)
+	}
+}
+
+func TestRemoveCoveringAllActions(t *testing.T) {
+	fs := NewFilters()
+	for cidr, action := range map[string]Action{
+		"10.0.0.0/8":  ActionDeny,
+		"10.1.0.0/16": ActionAccept,
+		"10.1.2.0/24": ActionNone,
+		"10.2.0.0/16": ActionDeny,
+	} {
+		_, ipnet, _ := net.ParseCIDR(cidr)
+		fs.AddFilter(*ipnet, action)
+	}
+
+	if n := RemoveCovering(fs, net.ParseIP("10.1.2.3")); n != 3 {
+		t.Fatalf("RemoveCovering removed %d rules, want 3", n)
+	}
+	if got := DenyCIDRStrings(fs); len(got) != 1 || got[0] != "10.2.0.0/16" {
+		t.Fatalf("expected [10.2.0.0/16] to remain, got %v", got)
+	}
+}
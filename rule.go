@@ -0,0 +1,24 @@
+package filter
+
+import "net"
+
+// Rule is a single filter rule: a network and the action applied to the
+// addresses it contains.
+type Rule struct {
+	Net    net.IPNet
+	Action Action
+}
+
+// Rules returns a copy of every rule in the Filters set, grouped by action.
+// Within an action, rules are listed in the order they were first added, even
+// if their action changed since; the relative order of rules with different
+// actions is not exposed by Filters.
+func Rules(fs *Filters) []Rule {
+	var rules []Rule
+	for _, action := range actions {
+		for _, ipnet := range fs.FiltersForAction(action) {
+			rules = append(rules, Rule{Net: ipnet, Action: action})
+		}
+	}
+	return rules
+}
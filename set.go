@@ -0,0 +1,50 @@
+package filter
+
+import (
+	"sync"
+
+	"github.com/multiformats/go-multiaddr"
+)
+
+// FilterSet is a registry of independent, named Filters sets, such as one
+// per listener or service.
+type FilterSet struct {
+	mu   sync.RWMutex
+	sets map[string]*Filters
+}
+
+// NewFilterSet constructs and returns a new, empty FilterSet.
+func NewFilterSet() *FilterSet {
+	return &FilterSet{
+		sets: make(map[string]*Filters),
+	}
+}
+
+// Add registers fs under the given name, replacing any set previously
+// registered under that name.
+func (s *FilterSet) Add(name string, fs *Filters) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	s.sets[name] = fs
+}
+
+// Get returns the Filters set registered under the given name, or nil if
+// there is none.
+func (s *FilterSet) Get(name string) *Filters {
+	s.mu.RLock()
+	defer s.mu.RUnlock()
+
+	return s.sets[name]
+}
+
+// AddrBlocked applies the Filters set registered under the given name to the
+// address. The ok result is false if no set is registered under that name;
+// callers decide whether that should fail open or closed.
+func (s *FilterSet) AddrBlocked(name string, a multiaddr.Multiaddr) (blocked, ok bool) {
+	fs := s.Get(name)
+	if fs == nil {
+		return false, false
+	}
+	return fs.AddrBlocked(a), true
+}
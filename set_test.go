@@ -0,0 +1,32 @@
+package filter
+
+import (
+	"net"
+	"testing"
+
+	"github.com/multiformats/go-multiaddr"
+)
+
+func TestFilterSetAddrBlocked(t *testing.T) {
+	fs := NewFilters()
+	_, ipnet, _ := net.ParseCIDR("10.0.0.0/8")
+	fs.AddFilter(*ipnet, ActionDeny)
+
+	s := NewFilterSet()
+	s.Add("public", fs)
+
+	for _, tc := range []struct {
+		name        string
+		addr        string
+		blocked, ok bool
+	}{
+		{"public", "/ip4/10.0.0.1/tcp/1", true, true},
+		{"public", "/ip4/1.2.3.4/tcp/1", false, true},
+		{"private", "/ip4/10.0.0.1/tcp/1", false, false},
+	} {
+		blocked, ok := s.AddrBlocked(tc.name, multiaddr.StringCast(tc.addr))
+		if blocked != tc.blocked || ok != tc.ok {
+			t.Errorf("AddrBlocked(%q, %s) = %v, %v, want %v, %v", tc.name, tc.addr, blocked, ok, tc.blocked, tc.ok)
+		}
+	}
+}
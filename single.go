@@ -0,0 +1,40 @@
+package filter
+
+import (
+	"fmt"
+	"net"
+)
+
+// DenyHost adds a deny rule for the single IP, as a /32 for IPv4 or a /128
+// for IPv6. It returns an error, and adds nothing, if ip is not a valid IP.
+func DenyHost(fs *Filters, ip net.IP) error {
+	return addHost(fs, ip, ActionDeny)
+}
+
+// AllowHost adds an accept rule for the single IP, as a /32 for IPv4 or a
+// /128 for IPv6. It returns an error, and adds nothing, if ip is not a valid
+// IP.
+func AllowHost(fs *Filters, ip net.IP) error {
+	return addHost(fs, ip, ActionAccept)
+}
+
+func addHost(fs *Filters, ip net.IP, action Action) error {
+	ipnet, ok := hostNet(ip)
+	if !ok {
+		return fmt.Errorf("invalid IP %q", ip)
+	}
+	fs.AddFilter(ipnet, action)
+	return nil
+}
+
+// hostNet returns the network containing only the given IP, and false if ip
+// is neither 4 nor 16 bytes long.
+func hostNet(ip net.IP) (net.IPNet, bool) {
+	if ip4 := ip.To4(); ip4 != nil {
+		return net.IPNet{IP: ip4, Mask: net.CIDRMask(8*net.IPv4len, 8*net.IPv4len)}, true
+	}
+	if ip16 := ip.To16(); ip16 != nil {
+		return net.IPNet{IP: ip16, Mask: net.CIDRMask(8*net.IPv6len, 8*net.IPv6len)}, true
+	}
+	return net.IPNet{}, false
+}
@@ -0,0 +1,34 @@
+package filter
+
+import (
+	"net"
+	"testing"
+)
+
+func TestDenyHost(t *testing.T) {
+	fs := NewFilters()
+	if err := DenyHost(fs, net.ParseIP("1.2.3.4")); err != nil {
+		t.Fatal(err)
+	}
+	if err := AllowHost(fs, net.ParseIP("2001:db8::1")); err != nil {
+		t.Fatal(err)
+	}
+	if got := DenyCIDRStrings(fs); len(got) != 1 || got[0] != "1.2.3.4/32" {
+		t.Fatalf("expected [1.2.3.4/32], got %v", got)
+	}
+	if got := AllowCIDRStrings(fs); len(got) != 1 || got[0] != "2001:db8::1/128" {
+		t.Fatalf("expected [2001:db8::1/128], got %v", got)
+	}
+
+	for _, ip := range []net.IP{nil, {1, 2, 3}} {
+		if err := DenyHost(fs, ip); err == nil {
+			t.Errorf("DenyHost(%v) should fail", ip)
+		}
+		if err := AllowHost(fs, ip); err == nil {
+			t.Errorf("AllowHost(%v) should fail", ip)
+		}
+	}
+	if n := len(Rules(fs)); n != 2 {
+		t.Fatalf("invalid IPs added rules: %v", Rules(fs))
+	}
+}
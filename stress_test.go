@@ -0,0 +1,78 @@
+package filter
+
+import (
+	"fmt"
+	"net"
+	"sync"
+	"testing"
+
+	"github.com/multiformats/go-multiaddr"
+	"golang.org/x/time/rate"
+)
+
+// TestConcurrentUse exercises the rule-management and query helpers from
+// several goroutines at once; it is meant to be run with -race.
+//
+// Invert, ResetToDefaults and anything else that assigns DefaultAction are
+// deliberately left out: DefaultAction is a plain field of go-multiaddr's
+// Filters, which AddrBlocked reads without holding the lock, so writing it
+// while the set is being queried is a race this package cannot guard
+// against. Set the default policy before sharing the set.
+func TestConcurrentUse(t *testing.T) {
+	const (
+		workers    = 4
+		iterations = 200
+	)
+
+	fs := NewFilters()
+	rf := NewRateLimited(fs, rate.Inf, 1)
+	hf := NewHostFilters()
+
+	var wg sync.WaitGroup
+	run := func(f func(i int)) {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			for i := 0; i < iterations; i++ {
+				f(i)
+			}
+		}()
+	}
+
+	for w := 0; w < workers; w++ {
+		w := w
+		run(func(i int) {
+			ip := net.IPv4(10, byte(w), byte(i), 1)
+			if err := DenyHost(fs, ip); err != nil {
+				t.Error(err)
+			}
+			_, ipnet, _ := net.ParseCIDR(fmt.Sprintf("10.%d.%d.0/24", w, i))
+			fs.AddFilter(*ipnet, ActionAccept)
+		})
+		run(func(i int) {
+			ip := net.IPv4(10, byte(w), byte(i), 1)
+			ipnet, _ := hostNet(ip)
+			fs.RemoveLiteral(ipnet)
+			RemoveCovering(fs, ip)
+		})
+		run(func(i int) {
+			a := multiaddr.StringCast(fmt.Sprintf("/ip4/10.%d.%d.1/tcp/1", w, i))
+			fs.AddrBlocked(a)
+			rf.AddrBlocked(a)
+			Decide(fs, a)
+			AddrBlockedUnwrapped(fs, a)
+		})
+		run(func(i int) {
+			Rules(fs)
+			Summary(fs)
+			Checksum(fs)
+		})
+		run(func(i int) {
+			if err := hf.AddDenyHost(fmt.Sprintf("*.host%d-%d.example", w, i)); err != nil {
+				t.Error(err)
+			}
+			hf.HostBlocked(fmt.Sprintf("a.host%d-%d.example", w, i))
+		})
+	}
+	wg.Wait()
+}
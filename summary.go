@@ -0,0 +1,19 @@
+package filter
+
+import "fmt"
+
+// Summary returns a compact, single-line description of the Filters set,
+// such as "filters(deny=320 allow=5 default=reject)", suitable for periodic
+// status logging. The default is reported as "reject" when the default
+// action denies addresses, and "allow" otherwise.
+func Summary(fs *Filters) string {
+	def := "allow"
+	if fs.DefaultAction == ActionDeny {
+		def = "reject"
+	}
+	return fmt.Sprintf("filters(deny=%d allow=%d default=%s)",
+		len(fs.FiltersForAction(ActionDeny)),
+		len(fs.FiltersForAction(ActionAccept)),
+		def,
+	)
+}
@@ -0,0 +1,17 @@
+package filter
+
+import "testing"
+
+func TestSummary(t *testing.T) {
+	fs := filtersFromRules(t, ActionDeny,
+		testRule{"10.0.0.0/8", ActionDeny},
+		testRule{"192.168.0.0/16", ActionDeny},
+		testRule{"10.1.0.0/16", ActionAccept},
+	)
+	if got, want := Summary(fs), "filters(deny=2 allow=1 default=reject)"; got != want {
+		t.Errorf("Summary = %q, want %q", got, want)
+	}
+	if got, want := Summary(NewFilters()), "filters(deny=0 allow=0 default=allow)"; got != want {
+		t.Errorf("Summary = %q, want %q", got, want)
+	}
+}
@@ -0,0 +1,55 @@
+package filter
+
+import (
+	"net"
+
+	"github.com/multiformats/go-multiaddr"
+)
+
+// AddrBlockedUnwrapped behaves like Filters.AddrBlocked, but also extracts
+// the IPv4 address embedded in 6to4 (2002::/16) and Teredo (2001::/32) IPv6
+// addresses and blocks the address if an explicit deny rule matches the
+// embedded IPv4 address. This prevents IPv4 deny rules from being bypassed
+// by dialing through a transition mechanism.
+//
+// The default policy is not applied to the embedded address; only the
+// wrapping IPv6 address is subject to it.
+func AddrBlockedUnwrapped(fs *Filters, a multiaddr.Multiaddr) bool {
+	if fs.AddrBlocked(a) {
+		return true
+	}
+
+	ip, found := addrIP(a)
+	if !found {
+		return false
+	}
+	v4 := embeddedIPv4(ip)
+	if v4 == nil {
+		return false
+	}
+	v4addr, err := ipMultiaddr(v4)
+	if err != nil {
+		return false
+	}
+	return Decide(fs, v4addr) == DecisionDeny
+}
+
+// embeddedIPv4 returns the IPv4 address embedded in a 6to4 or Teredo IPv6
+// address, or nil if the address is neither.
+func embeddedIPv4(ip net.IP) net.IP {
+	if len(ip) != net.IPv6len || ip.To4() != nil {
+		return nil
+	}
+
+	switch {
+	case ip[0] == 0x20 && ip[1] == 0x02:
+		// 6to4: 2002:AABB:CCDD::/48 embeds AA.BB.CC.DD.
+		return net.IPv4(ip[2], ip[3], ip[4], ip[5]).To4()
+	case ip[0] == 0x20 && ip[1] == 0x01 && ip[2] == 0x00 && ip[3] == 0x00:
+		// Teredo: the client's IPv4 address is stored, bit-inverted, in
+		// the last 32 bits.
+		return net.IPv4(^ip[12], ^ip[13], ^ip[14], ^ip[15]).To4()
+	default:
+		return nil
+	}
+}
@@ -0,0 +1,47 @@
+package filter
+
+import (
+	"net"
+	"testing"
+
+	"github.com/multiformats/go-multiaddr"
+)
+
+func TestAddrBlockedUnwrapped(t *testing.T) {
+	deny := NewFilters()
+	_, ipnet, _ := net.ParseCIDR("1.2.3.0/24")
+	deny.AddFilter(*ipnet, ActionDeny)
+
+	// Everything is denied by default, but the 6to4 and Teredo prefixes are
+	// explicitly accepted.
+	defaultDeny := NewFilters()
+	defaultDeny.DefaultAction = ActionDeny
+	for _, cidr := range []string{"2002::/16", "2001::/32"} {
+		_, ipnet, _ := net.ParseCIDR(cidr)
+		defaultDeny.AddFilter(*ipnet, ActionAccept)
+	}
+
+	for _, tc := range []struct {
+		name    string
+		fs      *Filters
+		addr    string
+		blocked bool
+	}{
+		{"ipv4", deny, "/ip4/1.2.3.4/tcp/1", true},
+		{"6to4", deny, "/ip6/2002:102:304::1/tcp/1", true},
+		{"6to4 other", deny, "/ip6/2002:102:404::1/tcp/1", false},
+		// The client IPv4 address 1.2.3.4 is stored inverted as fefd:fcfb.
+		{"teredo", deny, "/ip6/2001:0:4136:e378:8000:63bf:fefd:fcfb/tcp/1", true},
+		{"teredo uninverted", deny, "/ip6/2001:0:4136:e378:8000:63bf:102:304/tcp/1", false},
+		{"plain ipv6", deny, "/ip6/2001:db8::102:304/tcp/1", false},
+		{"default not applied", defaultDeny, "/ip6/2002:102:304::1/tcp/1", false},
+		{"default applied to wrapper", defaultDeny, "/ip6/2001:db8::1/tcp/1", true},
+	} {
+		t.Run(tc.name, func(t *testing.T) {
+			a := multiaddr.StringCast(tc.addr)
+			if got := AddrBlockedUnwrapped(tc.fs, a); got != tc.blocked {
+				t.Fatalf("AddrBlockedUnwrapped(%s) = %v, want %v", a, got, tc.blocked)
+			}
+		})
+	}
+}
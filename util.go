@@ -0,0 +1,46 @@
+package filter
+
+import (
+	"net"
+
+	"github.com/multiformats/go-multiaddr"
+)
+
+// addrIP extracts the IP address a multiaddr dials, using the same rules as
+// Filters.AddrBlocked: a leading ip6zone is skipped and only the first
+// component is considered.
+func addrIP(a multiaddr.Multiaddr) (ip net.IP, found bool) {
+	multiaddr.ForEach(a, func(c multiaddr.Component) bool {
+		switch c.Protocol().Code {
+		case multiaddr.P_IP6ZONE:
+			return true
+		case multiaddr.P_IP6, multiaddr.P_IP4:
+			found = true
+			ip = net.IP(c.RawValue())
+			return false
+		default:
+			return false
+		}
+	})
+	return ip, found
+}
+
+// ipMultiaddr returns the /ip4 or /ip6 multiaddr for the given IP.
+func ipMultiaddr(ip net.IP) (multiaddr.Multiaddr, error) {
+	if ip.To4() != nil {
+		return multiaddr.NewComponent("ip4", ip.String())
+	}
+	return multiaddr.NewComponent("ip6", ip.String())
+}
+
+// actionName returns the lower-case name of an Action.
+func actionName(action Action) string {
+	switch action {
+	case ActionAccept:
+		return "accept"
+	case ActionDeny:
+		return "deny"
+	default:
+		return "none"
+	}
+}
@@ -0,0 +1,83 @@
+package filter
+
+import (
+	"errors"
+	"fmt"
+	"net"
+	"strings"
+)
+
+// Validate checks every rule of the Filters set and returns an error
+// describing all the problems found, or nil if there are none. It reports
+// rules with a missing IP or mask, non-canonical masks, mismatched IP and
+// mask lengths, host bits set beyond the mask, and rules without an action.
+// Catch-all rules such as 0.0.0.0/0 are valid, as WithExplicitDefault
+// produces them, and are reported by ValidateWarnings instead.
+func Validate(fs *Filters) error {
+	var problems []string
+	for _, action := range actions {
+		for _, ipnet := range fs.FiltersForAction(action) {
+			rule := ruleString(ipnet)
+			for _, p := range validateIPNet(ipnet) {
+				problems = append(problems, fmt.Sprintf("rule %s: %s", rule, p))
+			}
+			if action == ActionNone {
+				problems = append(problems, fmt.Sprintf("rule %s: no action", rule))
+			}
+		}
+	}
+
+	if len(problems) == 0 {
+		return nil
+	}
+	return errors.New(strings.Join(problems, "; "))
+}
+
+// ValidateWarnings returns the rules of the Filters set that are valid but
+// often a mistake: catch-all rules, whose all-zero mask matches every address
+// of their family, overriding every rule added before them. Each warning
+// names the rule, as in the errors returned by Validate.
+func ValidateWarnings(fs *Filters) []string {
+	var warnings []string
+	for _, action := range actions {
+		for _, ipnet := range fs.FiltersForAction(action) {
+			if ones, bits := ipnet.Mask.Size(); bits != 0 && ones == 0 {
+				warnings = append(warnings, fmt.Sprintf("rule %s: mask matches every address", ruleString(ipnet)))
+			}
+		}
+	}
+	return warnings
+}
+
+func validateIPNet(ipnet net.IPNet) []string {
+	if ipnet.IP == nil {
+		return []string{"nil IP"}
+	}
+	if ipnet.Mask == nil {
+		return []string{"nil mask"}
+	}
+
+	_, bits := ipnet.Mask.Size()
+	if bits == 0 {
+		return []string{"non-canonical mask"}
+	}
+	if bits == 8*net.IPv4len && ipnet.IP.To4() == nil {
+		return []string{"IPv4 mask on an IPv6 address"}
+	}
+	if bits == 8*net.IPv6len && len(ipnet.IP) != net.IPv6len {
+		return []string{"IPv6 mask on an IPv4 address"}
+	}
+	if !ipnet.IP.Mask(ipnet.Mask).Equal(ipnet.IP) {
+		return []string{"host bits set"}
+	}
+	return nil
+}
+
+// ruleString formats a rule's network for error messages, falling back to
+// the raw IP and mask when the network is too malformed for IPNet.String.
+func ruleString(ipnet net.IPNet) string {
+	if s := ipnet.String(); s != "<nil>" {
+		return s
+	}
+	return fmt.Sprintf("%s/%s", ipnet.IP, ipnet.Mask)
+}
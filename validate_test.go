@@ -0,0 +1,42 @@
+package filter
+
+import (
+	"net"
+	"strings"
+	"testing"
+)
+
+func TestValidate(t *testing.T) {
+	fs := NewFilters()
+	_, ipnet, _ := net.ParseCIDR("10.0.0.0/8")
+	fs.AddFilter(*ipnet, ActionDeny)
+	fs.DefaultAction = ActionDeny
+	if err := Validate(WithExplicitDefault(fs)); err != nil {
+		t.Fatalf("WithExplicitDefault output failed validation: %s", err)
+	}
+
+	fs.AddFilter(net.IPNet{IP: net.ParseIP("1.2.3.4").To4(), Mask: net.CIDRMask(24, 32)}, ActionDeny)
+	err := Validate(fs)
+	if err == nil || !strings.Contains(err.Error(), "host bits set") {
+		t.Fatalf("expected a host bits error, got %v", err)
+	}
+}
+
+func TestValidateWarnings(t *testing.T) {
+	fs := NewFilters()
+	_, ipnet, _ := net.ParseCIDR("10.0.0.0/8")
+	fs.AddFilter(*ipnet, ActionDeny)
+	if got := ValidateWarnings(fs); len(got) != 0 {
+		t.Fatalf("expected no warnings, got %v", got)
+	}
+
+	got := ValidateWarnings(WithExplicitDefault(fs))
+	if len(got) != 2 {
+		t.Fatalf("expected a warning for each catch-all rule, got %v", got)
+	}
+	for _, w := range got {
+		if !strings.Contains(w, "mask matches every address") {
+			t.Errorf("unexpected warning %q", w)
+		}
+	}
+}
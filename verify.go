@@ -0,0 +1,50 @@
+package filter
+
+import (
+	"net"
+	"sort"
+
+	"github.com/multiformats/go-multiaddr"
+)
+
+// VerifyFailure describes an address for which a Filters set did not reach
+// the expected verdict.
+type VerifyFailure struct {
+	// Addr is the address as given to Verify.
+	Addr string
+	// Expected is the expected verdict, true meaning blocked.
+	Expected bool
+	// Got is the verdict the Filters set reached.
+	Got bool
+	// Err is set if Addr could not be parsed, in which case Got is
+	// meaningless.
+	Err error
+}
+
+// Verify checks the Filters set against a table of expected verdicts, keyed
+// by IP or multiaddr string and mapping to true for addresses that should be
+// blocked. It returns the entries whose verdict differs or that could not be
+// parsed, sorted by address.
+func Verify(fs *Filters, expected map[string]bool) []VerifyFailure {
+	var failures []VerifyFailure
+	for addr, blocked := range expected {
+		var got bool
+		if ip := net.ParseIP(addr); ip != nil {
+			got = IPBlocked(fs, ip)
+		} else {
+			a, err := multiaddr.NewMultiaddr(addr)
+			if err != nil {
+				failures = append(failures, VerifyFailure{Addr: addr, Expected: blocked, Err: err})
+				continue
+			}
+			got = fs.AddrBlocked(a)
+		}
+
+		if got != blocked {
+			failures = append(failures, VerifyFailure{Addr: addr, Expected: blocked, Got: got})
+		}
+	}
+
+	sort.Slice(failures, func(i, j int) bool { return failures[i].Addr < failures[j].Addr })
+	return failures
+}